@@ -0,0 +1,27 @@
+package katana
+
+// Build declares, within the body of an injector function, the providers katana-gen wires together
+// to build the function's result. Injector functions are meant to live in files excluded from regular
+// builds by the katanagen build tag:
+//
+//	//go:build katanagen
+//
+//	func InitializeAccountService(config Config) *AccountService {
+//		katana.Build(NewCache, NewDatastore, katana.Singleton(NewAccountService))
+//		return nil
+//	}
+//
+// katana-gen then generates the same function calling the providers in dependency order, with no
+// reflection involved. The function arguments are bound as values, whereas providers follow the same
+// semantics as Injector#ProvideNew unless marked with Singleton.
+//
+// Build panics when called, since injector functions are replaced by generated code.
+func Build(providers ...Provider) {
+	panic("katana.Build is a marker for katana-gen and must not be called")
+}
+
+// Singleton marks a provider given to Build as a singleton provider, following the same semantics
+// as Injector#ProvideSingleton.
+func Singleton(p Provider) Provider {
+	return p
+}
@@ -0,0 +1,60 @@
+package katana
+
+import (
+	"reflect"
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// Call injects the arguments of 'fn' and calls it, returning its result. It is a shorthand for
+// the common case of functions returning either a single value of type R or a value of type R
+// and an error:
+//
+//	accounts, err := katana.Call[[]*Account](injector, func(srv *AccountService) ([]*Account, error) {
+//		return srv.Accounts()
+//	})
+//
+// Failures resolving the arguments of 'fn' are returned as errors as well.
+func Call[R any](injector *Injector, fn interface{}) (result R, err error) {
+	typ := reflect.TypeOf(fn)
+
+	if typ.Kind() != reflect.Func {
+		return result, ErrNoSuchCallable{typ}
+	}
+
+	switch {
+	case typ.NumOut() == 1:
+	case typ.NumOut() == 2 && typ.Out(1) == errorType:
+	default:
+		return result, ErrInvalidCallable{typ}
+	}
+
+	callable, err := injector.tryInject(fn)
+	if err != nil {
+		return result, err
+	}
+
+	out := callable()
+	if err := out[:1].Into(&result); err != nil {
+		return result, err
+	}
+
+	if len(out) == 2 {
+		err = out.Err()
+	}
+
+	return result, err
+}
+
+// tryInject works like Injector#Inject, but returns the resolution failure as an error
+// rather than panicking.
+func (injector *Injector) tryInject(fn interface{}) (callable Callable, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = asError(r)
+			injector.resetTrace()
+		}
+	}()
+
+	return injector.Inject(fn), nil
+}
@@ -0,0 +1,78 @@
+package katana_test
+
+import (
+	"errors"
+	"github.com/drborges/katana"
+	"github.com/smartystreets/assertions/should"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestCall(t *testing.T) {
+	Convey("Given I have an injector with a few injectables", t, func() {
+		injector := katana.New().Provide(&Dependency{Field: "value"})
+
+		Convey("When I call a function returning a value and a nil error", func() {
+			field, err := katana.Call[string](injector, func(dep *Dependency) (string, error) {
+				return dep.Field, nil
+			})
+
+			Convey("Then its typed result is returned", func() {
+				So(err, should.BeNil)
+				So(field, should.Equal, "value")
+			})
+		})
+
+		Convey("When I call a function returning a value and an error", func() {
+			failure := errors.New("failure")
+			_, err := katana.Call[string](injector, func(dep *Dependency) (string, error) {
+				return "", failure
+			})
+
+			Convey("Then its error is returned", func() {
+				So(err, should.Equal, failure)
+			})
+		})
+
+		Convey("When I call a function returning a single value", func() {
+			dep, err := katana.Call[*Dependency](injector, func(dep *Dependency) *Dependency {
+				return dep
+			})
+
+			Convey("Then its typed result is returned", func() {
+				So(err, should.BeNil)
+				So(dep.Field, should.Equal, "value")
+			})
+		})
+
+		Convey("When I call a function whose arguments cannot be resolved", func() {
+			_, err := katana.Call[string](injector, func(dep *DependencyA) string {
+				return ""
+			})
+
+			Convey("Then the resolution error is returned", func() {
+				So(err, should.HaveSameTypeAs, katana.ErrNoSuchProvider{})
+			})
+		})
+
+		Convey("When I call a function returning a value of a different type", func() {
+			_, err := katana.Call[int](injector, func() string {
+				return ""
+			})
+
+			Convey("Then an output mismatch error is returned", func() {
+				So(err, should.HaveSameTypeAs, katana.ErrOutputMismatch{})
+			})
+		})
+
+		Convey("When I call a function with an unsupported output", func() {
+			_, err := katana.Call[string](injector, func() (string, string) {
+				return "", ""
+			})
+
+			Convey("Then an invalid callable error is returned", func() {
+				So(err, should.HaveSameTypeAs, katana.ErrInvalidCallable{})
+			})
+		})
+	})
+}
@@ -0,0 +1,23 @@
+module github.com/drborges/katana/cmd
+
+go 1.25.0
+
+require (
+	github.com/drborges/katana v0.0.0-00010101000000-000000000000
+	github.com/drborges/katana/katanavet v0.0.0
+	github.com/smartystreets/assertions v1.0.1
+	github.com/smartystreets/goconvey v1.6.4
+	golang.org/x/tools v0.45.0
+)
+
+require (
+	github.com/gopherjs/gopherjs v0.0.0-20181017120253-0766667cb4d1 // indirect
+	github.com/jtolds/gls v4.20.0+incompatible // indirect
+	golang.org/x/mod v0.36.0 // indirect
+	golang.org/x/sync v0.20.0 // indirect
+)
+
+replace (
+	github.com/drborges/katana => ../
+	github.com/drborges/katana/katanavet => ../katanavet
+)
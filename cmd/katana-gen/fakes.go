@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/types"
+	"golang.org/x/tools/imports"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const katanatestPath = "github.com/drborges/katana/katanatest"
+
+// FakesFile is the name of the test file generated in the package directory by GenerateFakes
+const FakesFile = "katana_fakes_test.go"
+
+// GenerateFakes loads the package in the given directory and generates, for each interface declared
+// in it, a fake forwarding its method calls to a katanatest.Recorder along with the registration of
+// the fake adapter, see katanatest.RegisterFake. Returns the path of the test file to be written
+// along with its contents.
+//
+// Generic interfaces, type constraints and interfaces that cannot be implemented outside of their
+// package are skipped.
+func GenerateFakes(dir string) (string, []byte, error) {
+	pkg, err := load(dir)
+	if err != nil {
+		return "", nil, err
+	}
+
+	gen := &fakes{pkg: pkg.Types, imports: map[string]string{katanatestPath: "katanatest"}}
+
+	scope := pkg.Types.Scope()
+	for _, name := range scope.Names() {
+		obj, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || obj.IsAlias() {
+			continue
+		}
+
+		named, ok := obj.Type().(*types.Named)
+		if !ok || named.TypeParams().Len() > 0 {
+			continue
+		}
+
+		if iface, ok := named.Underlying().(*types.Interface); ok && gen.fakeable(iface) {
+			gen.fake(obj.Name(), iface)
+		}
+	}
+
+	if len(gen.names) == 0 {
+		return "", nil, fmt.Errorf("no interfaces found in %v", pkg.PkgPath)
+	}
+
+	path := filepath.Join(filepath.Dir(pkg.GoFiles[0]), FakesFile)
+	src, err := imports.Process(path, gen.source(pkg.Name), nil)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return path, src, nil
+}
+
+// fakes accumulates the generated fakes of the interfaces of a package
+type fakes struct {
+	pkg     *types.Package
+	imports map[string]string
+	names   []string
+	body    bytes.Buffer
+}
+
+// fakeable returns true if the given interface has methods that can be implemented by a fake
+// declared in the package, none of which clashes with the embedded recorder.
+func (gen *fakes) fakeable(iface *types.Interface) bool {
+	if iface.NumMethods() == 0 || !iface.IsMethodSet() {
+		return false
+	}
+
+	for i := 0; i < iface.NumMethods(); i++ {
+		m := iface.Method(i)
+		if m.Name() == "Recorder" || !m.Exported() && m.Pkg() != gen.pkg {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (gen *fakes) source(pkgName string) []byte {
+	var paths []string
+	for p := range gen.imports {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by katana-gen -fakes. DO NOT EDIT.\n\npackage %v\n\nimport (\n", pkgName)
+	for _, p := range paths {
+		if name := gen.imports[p]; name != path.Base(p) {
+			fmt.Fprintf(&src, "%v %q\n", name, p)
+		} else {
+			fmt.Fprintf(&src, "%q\n", p)
+		}
+	}
+
+	src.WriteString(")\n\nfunc init() {\n")
+	for _, name := range gen.names {
+		fmt.Fprintf(&src, "katanatest.RegisterFake((*%v)(nil), func(r *katanatest.Recorder) %v { return %v{r} })\n", name, name, fakeName(name))
+	}
+	src.WriteString("}\n")
+
+	src.Write(gen.body.Bytes())
+	return src.Bytes()
+}
+
+// fake generates the fake of the given interface
+func (gen *fakes) fake(name string, iface *types.Interface) {
+	gen.names = append(gen.names, name)
+
+	fake := fakeName(name)
+	fmt.Fprintf(&gen.body, "\n// %v is a fake of %v recording its method calls\n", fake, name)
+	fmt.Fprintf(&gen.body, "type %v struct{ *katanatest.Recorder }\n", fake)
+
+	for i := 0; i < iface.NumMethods(); i++ {
+		gen.method(fake, iface.Method(i))
+	}
+}
+
+// method generates the given method of a fake, recording its calls and returning the values
+// configured in the recorder.
+func (gen *fakes) method(fake string, m *types.Func) {
+	sig := m.Type().(*types.Signature)
+
+	params := make([]string, sig.Params().Len())
+	args := []string{fmt.Sprintf("%q", m.Name())}
+	for i := range params {
+		name := fmt.Sprintf("p%v", i)
+		typ := sig.Params().At(i).Type()
+		if sig.Variadic() && i == len(params)-1 {
+			params[i] = name + " ..." + gen.typeString(typ.(*types.Slice).Elem())
+		} else {
+			params[i] = name + " " + gen.typeString(typ)
+		}
+		args = append(args, name)
+	}
+
+	results := make([]string, sig.Results().Len())
+	vars := make([]string, len(results))
+	ptrs := make([]string, len(results))
+	for i := range results {
+		results[i] = gen.typeString(sig.Results().At(i).Type())
+		vars[i] = fmt.Sprintf("r%v", i)
+		ptrs[i] = "&" + vars[i]
+	}
+
+	result := strings.Join(results, ", ")
+	if len(results) > 1 {
+		result = "(" + result + ")"
+	}
+
+	record := fmt.Sprintf("fake.Recorder.Record(%v)", strings.Join(args, ", "))
+
+	fmt.Fprintf(&gen.body, "\nfunc (fake %v) %v(%v) %v {\n", fake, m.Name(), strings.Join(params, ", "), result)
+	if len(results) == 0 {
+		fmt.Fprintf(&gen.body, "%v\n}\n", record)
+		return
+	}
+
+	for i, v := range vars {
+		fmt.Fprintf(&gen.body, "var %v %v\n", v, results[i])
+	}
+	fmt.Fprintf(&gen.body, "if err := %v.Into(%v); err != nil {\npanic(err)\n}\n", record, strings.Join(ptrs, ", "))
+	fmt.Fprintf(&gen.body, "return %v\n}\n", strings.Join(vars, ", "))
+}
+
+// typeString returns the given type as written in the package, recording the imports it needs
+func (gen *fakes) typeString(typ types.Type) string {
+	return types.TypeString(typ, func(pkg *types.Package) string {
+		if pkg == gen.pkg {
+			return ""
+		}
+		gen.imports[pkg.Path()] = pkg.Name()
+		return pkg.Name()
+	})
+}
+
+// fakeName returns the name of the fake of the given interface
+func fakeName(iface string) string {
+	r, size := utf8.DecodeRuneInString(iface)
+	return "fake" + string(unicode.ToUpper(r)) + iface[size:]
+}
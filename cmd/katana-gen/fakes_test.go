@@ -0,0 +1,94 @@
+package main
+
+import (
+	"github.com/smartystreets/assertions/should"
+	. "github.com/smartystreets/goconvey/convey"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateFakes(t *testing.T) {
+	Convey("Given I have a package declaring interfaces", t, func() {
+		path, src, err := GenerateFakes("testdata/fakes")
+
+		Convey("Then fakes of the interfaces that can be faked are generated", func() {
+			So(err, should.BeNil)
+			So(filepath.Base(path), should.Equal, FakesFile)
+			So(string(src), should.Equal, `// Code generated by katana-gen -fakes. DO NOT EDIT.
+
+package fakes
+
+import (
+	"context"
+
+	"github.com/drborges/katana/katanatest"
+)
+
+func init() {
+	katanatest.RegisterFake((*Mailer)(nil), func(r *katanatest.Recorder) Mailer { return fakeMailer{r} })
+	katanatest.RegisterFake((*Store)(nil), func(r *katanatest.Recorder) Store { return fakeStore{r} })
+	katanatest.RegisterFake((*notifier)(nil), func(r *katanatest.Recorder) notifier { return fakeNotifier{r} })
+}
+
+// fakeMailer is a fake of Mailer recording its method calls
+type fakeMailer struct{ *katanatest.Recorder }
+
+func (fake fakeMailer) Close() error {
+	var r0 error
+	if err := fake.Recorder.Record("Close").Into(&r0); err != nil {
+		panic(err)
+	}
+	return r0
+}
+
+func (fake fakeMailer) Send(p0 string, p1 string) error {
+	var r0 error
+	if err := fake.Recorder.Record("Send", p0, p1).Into(&r0); err != nil {
+		panic(err)
+	}
+	return r0
+}
+
+// fakeStore is a fake of Store recording its method calls
+type fakeStore struct{ *katanatest.Recorder }
+
+func (fake fakeStore) Find(p0 context.Context, p1 string) (*User, error) {
+	var r0 *User
+	var r1 error
+	if err := fake.Recorder.Record("Find", p0, p1).Into(&r0, &r1); err != nil {
+		panic(err)
+	}
+	return r0, r1
+}
+
+func (fake fakeStore) Flush() {
+	fake.Recorder.Record("Flush")
+}
+
+func (fake fakeStore) Save(p0 ...*User) error {
+	var r0 error
+	if err := fake.Recorder.Record("Save", p0).Into(&r0); err != nil {
+		panic(err)
+	}
+	return r0
+}
+
+// fakeNotifier is a fake of notifier recording its method calls
+type fakeNotifier struct{ *katanatest.Recorder }
+
+func (fake fakeNotifier) Notify(p0 *User) {
+	fake.Recorder.Record("Notify", p0)
+}
+`)
+		})
+	})
+
+	Convey("Given I have a package declaring no interfaces", t, func() {
+		_, _, err := GenerateFakes("testdata/app")
+
+		Convey("Then it reports there is nothing to fake", func() {
+			So(err, should.NotBeNil)
+			So(err.Error(), should.StartWith, "no interfaces found in")
+		})
+	})
+}
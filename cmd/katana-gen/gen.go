@@ -0,0 +1,384 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/printer"
+	"go/token"
+	"go/types"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/imports"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const katanaPath = "github.com/drborges/katana"
+
+// OutputFile is the name of the file generated in the package directory
+const OutputFile = "katana_gen.go"
+
+// Errors holds the wiring errors found by the generator, sorted by position
+type Errors []error
+
+func (errs Errors) Error() string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// WiringError is an error found in an injector function at a given position
+type WiringError struct {
+	Pos token.Position
+	Msg string
+}
+
+func (err WiringError) Error() string {
+	return fmt.Sprintf("%v: %v", err.Pos, err.Msg)
+}
+
+// provider is a provider function given to katana.Build
+type provider struct {
+	expr      string
+	signature *types.Signature
+	singleton bool
+	pos       token.Pos
+}
+
+func (p *provider) result() types.Type {
+	return p.signature.Results().At(0).Type()
+}
+
+// Generate loads the package in the given directory, with the katanagen build tag set, and generates
+// the code of the injector functions declared in it. Returns the path of the file to be written along
+// with its contents.
+func Generate(dir string) (string, []byte, error) {
+	pkg, err := load(dir, "-tags=katanagen")
+	if err != nil {
+		return "", nil, err
+	}
+
+	gen := &generator{pkg: pkg}
+	for _, file := range pkg.Syntax {
+		if hasBuildTag(file, "katanagen") {
+			gen.file(file)
+		}
+	}
+
+	if len(gen.errs) > 0 {
+		sort.Slice(gen.errs, func(i, j int) bool {
+			return gen.errs[i].(WiringError).Pos.String() < gen.errs[j].(WiringError).Pos.String()
+		})
+		return "", nil, gen.errs
+	}
+
+	if gen.injectors == 0 {
+		return "", nil, fmt.Errorf("no injector functions found in %v", pkg.PkgPath)
+	}
+
+	path := filepath.Join(filepath.Dir(pkg.GoFiles[0]), OutputFile)
+	src, err := imports.Process(path, gen.source(), nil)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return path, src, nil
+}
+
+// load loads the package in the given directory with the given build flags
+func load(dir string, flags ...string) (*packages.Package, error) {
+	cfg := &packages.Config{
+		Mode:       packages.NeedName | packages.NeedFiles | packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo,
+		Dir:        dir,
+		BuildFlags: flags,
+	}
+
+	pkgs, err := packages.Load(cfg, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected a single package in %v, found %v", dir, len(pkgs))
+	}
+
+	pkg := pkgs[0]
+	if len(pkg.Errors) > 0 {
+		return nil, fmt.Errorf("loading %v: %v", pkg.PkgPath, pkg.Errors[0])
+	}
+
+	return pkg, nil
+}
+
+// generator accumulates the generated code of the injector functions of a package
+type generator struct {
+	pkg       *packages.Package
+	imports   []string
+	body      bytes.Buffer
+	injectors int
+	errs      Errors
+}
+
+func (gen *generator) errorf(pos token.Pos, format string, args ...interface{}) {
+	gen.errs = append(gen.errs, WiringError{gen.pkg.Fset.Position(pos), fmt.Sprintf(format, args...)})
+}
+
+func (gen *generator) source() []byte {
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by katana-gen. DO NOT EDIT.\n\n//go:build !katanagen\n\npackage %v\n\n", gen.pkg.Name)
+	fmt.Fprintf(&src, "import (\n%v\n)\n\n", strings.Join(gen.imports, "\n"))
+	src.Write(gen.body.Bytes())
+	return src.Bytes()
+}
+
+// file generates the injector functions declared in the given file
+func (gen *generator) file(file *ast.File) {
+	for _, spec := range file.Imports {
+		if spec.Path.Value != `"`+katanaPath+`"` && !gen.imported(spec) {
+			gen.imports = append(gen.imports, gen.print(spec))
+		}
+	}
+
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok {
+			if build := gen.buildCall(fn); build != nil {
+				gen.injector(fn, build)
+			}
+		}
+	}
+}
+
+func (gen *generator) imported(spec *ast.ImportSpec) bool {
+	for _, imp := range gen.imports {
+		if imp == gen.print(spec) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildCall returns the katana.Build call in the body of the given function, if any
+func (gen *generator) buildCall(fn *ast.FuncDecl) *ast.CallExpr {
+	if fn.Body == nil {
+		return nil
+	}
+
+	for _, stmt := range fn.Body.List {
+		if expr, ok := stmt.(*ast.ExprStmt); ok {
+			if call, ok := expr.X.(*ast.CallExpr); ok && gen.isKatanaFunc(call, "Build") {
+				return call
+			}
+		}
+	}
+
+	return nil
+}
+
+// isKatanaFunc returns true if the given call is a call to the given katana function
+func (gen *generator) isKatanaFunc(call *ast.CallExpr, name string) bool {
+	var ident *ast.Ident
+	switch fun := call.Fun.(type) {
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+	case *ast.Ident:
+		ident = fun
+	default:
+		return false
+	}
+
+	obj, ok := gen.pkg.TypesInfo.Uses[ident].(*types.Func)
+	return ok && obj.Pkg() != nil && obj.Pkg().Path() == katanaPath && obj.Name() == name
+}
+
+// injector generates the given injector function from the providers given to katana.Build
+func (gen *generator) injector(fn *ast.FuncDecl, build *ast.CallExpr) {
+	sig := gen.pkg.TypesInfo.Defs[fn.Name].Type().(*types.Signature)
+	if sig.Results().Len() != 1 {
+		gen.errorf(fn.Pos(), "injector function %v must return exactly one value", fn.Name.Name)
+		return
+	}
+
+	w := &wiring{gen: gen, names: make(map[string]bool), built: make(map[*provider]string)}
+
+	for i := 0; i < sig.Params().Len(); i++ {
+		param := sig.Params().At(i)
+		if param.Name() == "" || param.Name() == "_" {
+			gen.errorf(fn.Pos(), "injector function %v parameters must be named", fn.Name.Name)
+			return
+		}
+		w.params = append(w.params, param)
+		w.names[param.Name()] = true
+	}
+
+	for _, arg := range build.Args {
+		if p := gen.provider(arg); p != nil {
+			if other := w.lookup(p.result()); other != nil {
+				gen.errorf(p.pos, "multiple providers of %v: %v and %v", p.result(), other.expr, p.expr)
+				continue
+			}
+			w.providers = append(w.providers, p)
+		}
+	}
+
+	result, ok := w.resolve(sig.Results().At(0).Type(), fn.Name.Name, build.Pos())
+	if !ok {
+		return
+	}
+
+	gen.injectors++
+
+	if fn.Doc != nil {
+		for _, comment := range fn.Doc.List {
+			gen.body.WriteString(comment.Text + "\n")
+		}
+	}
+
+	fmt.Fprintf(&gen.body, "func %v%v {\n", fn.Name.Name, strings.TrimPrefix(gen.print(fn.Type), "func"))
+	for _, line := range w.lines {
+		fmt.Fprintf(&gen.body, "\t%v\n", line)
+	}
+	fmt.Fprintf(&gen.body, "\treturn %v\n}\n\n", result)
+}
+
+// provider parses the given katana.Build argument
+func (gen *generator) provider(arg ast.Expr) *provider {
+	p := &provider{pos: arg.Pos()}
+
+	if call, ok := arg.(*ast.CallExpr); ok && gen.isKatanaFunc(call, "Singleton") && len(call.Args) == 1 {
+		p.singleton = true
+		arg = call.Args[0]
+	}
+
+	sig, ok := gen.pkg.TypesInfo.TypeOf(arg).(*types.Signature)
+	if !ok {
+		gen.errorf(arg.Pos(), "provider %v is not a function", gen.print(arg))
+		return nil
+	}
+
+	if sig.Results().Len() != 1 || sig.Variadic() {
+		gen.errorf(arg.Pos(), "invalid provider function %v: %v", gen.print(arg), sig)
+		return nil
+	}
+
+	p.expr = gen.print(arg)
+	p.signature = sig
+	return p
+}
+
+func (gen *generator) print(node interface{}) string {
+	var buf bytes.Buffer
+	printer.Fprint(&buf, gen.pkg.Fset, node)
+	return buf.String()
+}
+
+// wiring resolves the dependency graph of an injector function
+type wiring struct {
+	gen       *generator
+	params    []*types.Var
+	providers []*provider
+	// built holds the variables holding singleton instances
+	built map[*provider]string
+	names map[string]bool
+	trace []string
+	lines []string
+}
+
+func (w *wiring) lookup(typ types.Type) *provider {
+	for _, p := range w.providers {
+		if types.Identical(p.result(), typ) {
+			return p
+		}
+	}
+	return nil
+}
+
+// resolve emits the calls needed to build an instance of the given type, returning the name of
+// the variable holding it.
+func (w *wiring) resolve(typ types.Type, dependent string, pos token.Pos) (string, bool) {
+	for _, param := range w.params {
+		if types.Identical(param.Type(), typ) {
+			return param.Name(), true
+		}
+	}
+
+	p := w.lookup(typ)
+	if p == nil {
+		w.gen.errorf(pos, "no provider found for %v, needed by %v", w.qualify(typ), dependent)
+		return "", false
+	}
+
+	if name, built := w.built[p]; built {
+		return name, true
+	}
+
+	name := w.qualify(typ)
+	for _, t := range w.trace {
+		if t == name {
+			w.gen.errorf(p.pos, "cyclic dependency detected: [%v -> %v]", strings.Join(w.trace, " -> "), name)
+			return "", false
+		}
+	}
+
+	w.trace = append(w.trace, name)
+	defer func() { w.trace = w.trace[:len(w.trace)-1] }()
+
+	args := make([]string, p.signature.Params().Len())
+	for i := range args {
+		arg, ok := w.resolve(p.signature.Params().At(i).Type(), name, p.pos)
+		if !ok {
+			return "", false
+		}
+		args[i] = arg
+	}
+
+	variable := w.variable(typ)
+	w.lines = append(w.lines, fmt.Sprintf("%v := %v(%v)", variable, p.expr, strings.Join(args, ", ")))
+
+	if p.singleton {
+		w.built[p] = variable
+	}
+
+	return variable, true
+}
+
+// variable returns a new variable name for an instance of the given type
+func (w *wiring) variable(typ types.Type) string {
+	base := "value"
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+	if named, ok := typ.(*types.Named); ok {
+		base = named.Obj().Name()
+		base = strings.ToLower(base[:1]) + base[1:]
+	}
+
+	name := base
+	for i := 2; w.names[name] || token.IsKeyword(name); i++ {
+		name = fmt.Sprintf("%v%v", base, i)
+	}
+
+	w.names[name] = true
+	return name
+}
+
+func (w *wiring) qualify(typ types.Type) string {
+	return types.TypeString(typ, types.RelativeTo(w.gen.pkg.Types))
+}
+
+// hasBuildTag returns true if the given file has a //go:build constraint mentioning the given tag
+func hasBuildTag(file *ast.File, tag string) bool {
+	for _, group := range file.Comments {
+		if group.Pos() > file.Package {
+			break
+		}
+		for _, comment := range group.List {
+			if strings.HasPrefix(comment.Text, "//go:build") && strings.Contains(comment.Text, tag) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"github.com/smartystreets/assertions/should"
+	. "github.com/smartystreets/goconvey/convey"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	Convey("Given I have a package declaring injector functions", t, func() {
+		path, src, err := Generate("testdata/app")
+
+		Convey("Then the injector functions are generated", func() {
+			So(err, should.BeNil)
+			So(filepath.Base(path), should.Equal, OutputFile)
+			So(string(src), should.Equal, `// Code generated by katana-gen. DO NOT EDIT.
+
+//go:build !katanagen
+
+package app
+
+// InitializeAccountService builds an *AccountService
+func InitializeAccountService(config Config) *AccountService {
+	cache := NewCache(config)
+	datastore := NewDatastore(config, cache)
+	accountService := NewAccountService(datastore, cache)
+	return accountService
+}
+
+func InitializeDatastore(config Config) *Datastore {
+	cache := NewCache(config)
+	datastore := NewDatastore(config, cache)
+	return datastore
+}
+`)
+		})
+	})
+
+	Convey("Given I have an injector function with a missing provider", t, func() {
+		_, _, err := Generate("testdata/missing")
+
+		Convey("Then it reports the missing provider", func() {
+			So(err, should.NotBeNil)
+			So(err.Error(), should.EndWith, "no provider found for *Cache, needed by *Datastore")
+		})
+	})
+
+	Convey("Given I have an injector function with cyclic dependencies", t, func() {
+		_, _, err := Generate("testdata/cyclic")
+
+		Convey("Then it reports the cyclic dependency", func() {
+			So(err, should.NotBeNil)
+			So(err.Error(), should.EndWith, "cyclic dependency detected: [*A -> *B -> *A]")
+		})
+	})
+}
@@ -0,0 +1,53 @@
+// Command katana-gen generates plain Go code wiring together the providers declared with katana.Build,
+// detecting missing and cyclic dependencies at generation time rather than at runtime.
+//
+// Usage:
+//
+//	katana-gen [-fakes] [dir]
+//
+// katana-gen loads the package in the given directory -- the current one by default -- with the
+// katanagen build tag set, and writes the generated injector functions to katana_gen.go. It is meant
+// to be used with go generate:
+//
+//	//go:generate katana-gen
+//
+// With -fakes, katana-gen instead writes to katana_fakes_test.go fakes of the interfaces declared in
+// the package, registered with katanatest.RegisterFake so that katanatest.Injector#FakeUnbound can
+// provide them.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	fakes := flag.Bool("fakes", false, "generate fakes of the interfaces declared in the package")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: katana-gen [-fakes] [dir]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+
+	generate := Generate
+	if *fakes {
+		generate = GenerateFakes
+	}
+
+	path, src, err := generate(dir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if err := os.WriteFile(path, src, 0644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,32 @@
+package app
+
+type Config struct {
+	DatastoreURL string
+	CacheTTL     int
+}
+
+type Cache struct {
+	TTL int
+}
+
+type Datastore struct {
+	Cache *Cache
+	URL   string
+}
+
+type AccountService struct {
+	Datastore *Datastore
+	Cache     *Cache
+}
+
+func NewCache(config Config) *Cache {
+	return &Cache{config.CacheTTL}
+}
+
+func NewDatastore(config Config, cache *Cache) *Datastore {
+	return &Datastore{cache, config.DatastoreURL}
+}
+
+func NewAccountService(db *Datastore, cache *Cache) *AccountService {
+	return &AccountService{db, cache}
+}
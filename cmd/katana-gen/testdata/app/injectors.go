@@ -0,0 +1,18 @@
+//go:build katanagen
+
+package app
+
+import (
+	"github.com/drborges/katana"
+)
+
+// InitializeAccountService builds an *AccountService
+func InitializeAccountService(config Config) *AccountService {
+	katana.Build(NewAccountService, NewDatastore, katana.Singleton(NewCache))
+	return nil
+}
+
+func InitializeDatastore(config Config) *Datastore {
+	katana.Build(NewDatastore, NewCache)
+	return nil
+}
@@ -0,0 +1,9 @@
+package cyclic
+
+type A struct{ B *B }
+
+type B struct{ A *A }
+
+func NewA(b *B) *A { return &A{b} }
+
+func NewB(a *A) *B { return &B{a} }
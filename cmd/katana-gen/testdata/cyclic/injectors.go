@@ -0,0 +1,10 @@
+//go:build katanagen
+
+package cyclic
+
+import "github.com/drborges/katana"
+
+func InitializeA() *A {
+	katana.Build(NewA, NewB)
+	return nil
+}
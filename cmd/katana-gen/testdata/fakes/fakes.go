@@ -0,0 +1,37 @@
+package fakes
+
+import (
+	"context"
+	"io"
+)
+
+type User struct {
+	ID string
+}
+
+type Store interface {
+	Find(ctx context.Context, id string) (*User, error)
+	Save(users ...*User) error
+	Flush()
+}
+
+type Mailer interface {
+	io.Closer
+	Send(to, body string) error
+}
+
+type notifier interface {
+	Notify(user *User)
+}
+
+// Interfaces that cannot be faked are skipped
+
+type Empty interface{}
+
+type Number interface {
+	~int | ~float64
+}
+
+type Getter[T any] interface {
+	Get() T
+}
@@ -0,0 +1,10 @@
+//go:build katanagen
+
+package missing
+
+import "github.com/drborges/katana"
+
+func InitializeDatastore() *Datastore {
+	katana.Build(NewDatastore)
+	return nil
+}
@@ -0,0 +1,11 @@
+package missing
+
+type Cache struct{}
+
+type Datastore struct {
+	Cache *Cache
+}
+
+func NewDatastore(cache *Cache) *Datastore {
+	return &Datastore{cache}
+}
@@ -0,0 +1,13 @@
+// Command katanavet reports misuses of the katana API. It may be run standalone or through go vet:
+//
+//	go vet -vettool=$(which katanavet) ./...
+package main
+
+import (
+	"github.com/drborges/katana/katanavet"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(katanavet.Analyzer)
+}
@@ -0,0 +1,233 @@
+package katana
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// ProfilesEnv is the environment variable listing the active profiles, comma separated, unless
+// profiles are explicitly activated with Injector#ActivateProfiles
+const ProfilesEnv = "KATANA_PROFILES"
+
+var boolType = reflect.TypeOf(true)
+
+// Condition decides whether a conditional binding is registered, see Injector#When
+type Condition struct {
+	// Description describes the condition in reports, e.g. "profile dev"
+	Description string
+	eval        func(injector *Injector) (bool, error)
+}
+
+// Profile is a condition holding when any of the given profiles is active
+func Profile(profiles ...string) Condition {
+	return Condition{
+		Description: "profile " + strings.Join(profiles, "|"),
+		eval: func(injector *Injector) (bool, error) {
+			for _, active := range injector.ActiveProfiles() {
+				for _, profile := range profiles {
+					if profile == active {
+						return true, nil
+					}
+				}
+			}
+			return false, nil
+		},
+	}
+}
+
+// Predicate is a condition holding when fn returns true. The arguments of fn are injected, so
+// predicates can depend on already bound types such as config structs:
+//
+//	katana.Predicate("cache enabled", func(cfg *Config) bool { return cfg.CacheEnabled })
+func Predicate(description string, fn interface{}) Condition {
+	typ := reflect.TypeOf(fn)
+	if typ == nil || typ.Kind() != reflect.Func || typ.NumOut() != 1 || typ.Out(0) != boolType {
+		panic(ErrInvalidCallable{typ})
+	}
+
+	return Condition{
+		Description: description,
+		eval: func(injector *Injector) (bool, error) {
+			callable, err := injector.tryInject(fn)
+			if err != nil {
+				return false, err
+			}
+			return callable().First().(bool), nil
+		},
+	}
+}
+
+// ConditionalBinding describes a binding registered with Injector#When
+type ConditionalBinding struct {
+	Type           reflect.Type
+	InjectableType InjectableType
+	Condition      string
+	// Evaluated is false until the conditions are evaluated by Injector#Freeze or Injector#Start
+	Evaluated bool
+	// Selected is true in case the condition held and the binding was registered
+	Selected bool
+	// Reason explains why the binding was selected or skipped
+	Reason string
+}
+
+// Conditional registers bindings applying only when its condition holds, see Injector#When
+type Conditional struct {
+	injector  *Injector
+	condition Condition
+}
+
+// conditional is a pending conditional binding
+type conditional struct {
+	binding   *ConditionalBinding
+	condition Condition
+	register  func(injector *Injector)
+}
+
+// ActivateProfiles sets the active profiles of the injector, overriding the ones listed by the
+// KATANA_PROFILES environment variable
+func (injector *Injector) ActivateProfiles(profiles ...string) *Injector {
+	injector.profiles = append([]string{}, profiles...)
+	return injector
+}
+
+// ActiveProfiles returns the active profiles of the injector
+func (injector *Injector) ActiveProfiles() []string {
+	if injector.profiles != nil {
+		return injector.profiles
+	}
+
+	var profiles []string
+	for _, profile := range strings.Split(os.Getenv(ProfilesEnv), ",") {
+		if profile = strings.TrimSpace(profile); profile != "" {
+			profiles = append(profiles, profile)
+		}
+	}
+	return profiles
+}
+
+// When returns a Conditional registering bindings only when the given condition holds:
+//
+//	injector.
+//		When(katana.Profile("dev", "test")).ProvideNew((*Store)(nil), NewMemoryStore).
+//		When(katana.Profile("prod")).ProvideNew((*Store)(nil), NewPostgresStore)
+//
+// Conditions are evaluated once, in registration order, by Injector#Freeze or Injector#Start,
+// whichever comes first, so predicates may depend on unconditional bindings as well as on
+// conditional bindings selected before them.
+func (injector *Injector) When(condition Condition) *Conditional {
+	return &Conditional{injector, condition}
+}
+
+// ProvideNew works like Injector#ProvideNew in case the condition holds
+func (c *Conditional) ProvideNew(injectable interface{}, p Provider) *Injector {
+	return c.add(keyOf(injectable), TypeNew, func(injector *Injector) {
+		injector.ProvideNew(injectable, p)
+	})
+}
+
+// ProvideSingleton works like Injector#ProvideSingleton in case the condition holds
+func (c *Conditional) ProvideSingleton(injectable interface{}, p Provider) *Injector {
+	return c.add(keyOf(injectable), TypeSingleton, func(injector *Injector) {
+		injector.ProvideSingleton(injectable, p)
+	})
+}
+
+// ProvideEagerSingleton works like Injector#ProvideEagerSingleton in case the condition holds
+func (c *Conditional) ProvideEagerSingleton(injectable interface{}, p Provider) *Injector {
+	return c.add(keyOf(injectable), TypeSingleton, func(injector *Injector) {
+		injector.ProvideEagerSingleton(injectable, p)
+	})
+}
+
+// ProvideAs works like Injector#ProvideAs in case the condition holds
+func (c *Conditional) ProvideAs(injectable, instance interface{}) *Injector {
+	return c.add(keyOf(injectable), TypeSingleton, func(injector *Injector) {
+		injector.ProvideAs(injectable, instance)
+	})
+}
+
+func (c *Conditional) add(typ reflect.Type, injType InjectableType, register func(*Injector)) *Injector {
+	if c.injector.frozen {
+		panic(ErrFrozenInjector{typ})
+	}
+
+	c.injector.conditionals = append(c.injector.conditionals, &conditional{
+		binding: &ConditionalBinding{
+			Type:           typ,
+			InjectableType: injType,
+			Condition:      c.condition.Description,
+			Reason:         "conditions are evaluated by Freeze or Start",
+		},
+		condition: c.condition,
+		register:  register,
+	})
+
+	return c.injector
+}
+
+// ConditionalBindings returns the bindings registered with Injector#When in registration order,
+// describing whether they were selected or skipped and why
+func (injector *Injector) ConditionalBindings() []ConditionalBinding {
+	bindings := make([]ConditionalBinding, len(injector.conditionals))
+	for i, c := range injector.conditionals {
+		bindings[i] = *c.binding
+	}
+	return bindings
+}
+
+// evaluateConditions evaluates the conditions of pending conditional bindings, registering the
+// selected ones. Evaluation stops at the first condition failing to evaluate.
+func (injector *Injector) evaluateConditions() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = asError(r)
+		}
+	}()
+
+	for _, c := range injector.conditionals {
+		if c.binding.Evaluated {
+			continue
+		}
+
+		holds, err := c.condition.eval(injector)
+		if err != nil {
+			return ErrConditionFailed{c.binding.Type, c.condition.Description, err}
+		}
+
+		c.binding.Evaluated = true
+		c.binding.Selected = holds
+
+		if !holds {
+			c.binding.Reason = fmt.Sprintf("%v does not hold", c.condition.Description)
+			continue
+		}
+
+		c.binding.Reason = fmt.Sprintf("%v holds", c.condition.Description)
+		c.register(injector)
+	}
+
+	return nil
+}
+
+// cloneConditionals returns a copy of the conditional bindings, so that clones evaluate them
+// on their own
+func (injector *Injector) cloneConditionals() []*conditional {
+	conditionals := make([]*conditional, len(injector.conditionals))
+	for i, c := range injector.conditionals {
+		binding := *c.binding
+		conditionals[i] = &conditional{&binding, c.condition, c.register}
+	}
+	return conditionals
+}
+
+// skipped returns the conditional binding explaining why typ has no registered provider, if any
+func (injector *Injector) skipped(typ reflect.Type) (ConditionalBinding, bool) {
+	for _, c := range injector.conditionals {
+		if c.binding.Type == typ && !c.binding.Selected {
+			return *c.binding, true
+		}
+	}
+	return ConditionalBinding{}, false
+}
@@ -0,0 +1,149 @@
+package katana_test
+
+import (
+	"context"
+	"errors"
+	"github.com/drborges/katana"
+	"github.com/smartystreets/assertions/should"
+	. "github.com/smartystreets/goconvey/convey"
+	"os"
+	"reflect"
+	"testing"
+)
+
+type FeatureConfig struct {
+	CacheEnabled bool
+}
+
+func TestInjectorWhen(t *testing.T) {
+	Convey("Given I have bindings conditioned on profiles", t, func() {
+		injector := katana.New().
+			When(katana.Profile("dev", "test")).ProvideAs((*InterfaceDependency)(nil), &InterfaceDependencyImpl{"memory"}).
+			When(katana.Profile("prod")).ProvideAs((*InterfaceDependency)(nil), &InterfaceDependencyImpl{"postgres"})
+
+		Convey("When conditions are not evaluated yet", func() {
+			Convey("Then no binding is registered", func() {
+				So(injector.Has((*InterfaceDependency)(nil)), should.BeFalse)
+				So(injector.ConditionalBindings()[0].Evaluated, should.BeFalse)
+			})
+		})
+
+		Convey("When I freeze the injector with the prod profile active", func() {
+			injector.ActivateProfiles("prod").Freeze()
+
+			Convey("Then only the prod binding is selected", func() {
+				var dep InterfaceDependency
+				injector.Resolve(&dep)
+
+				So(dep.(*InterfaceDependencyImpl).Field, should.Equal, "postgres")
+			})
+
+			Convey("Then the bindings report why they were selected or skipped", func() {
+				bindings := injector.ConditionalBindings()
+
+				So(len(bindings), should.Equal, 2)
+				So(bindings[0].Type, should.Equal, reflect.TypeOf((*InterfaceDependency)(nil)).Elem())
+				So(bindings[0].Evaluated, should.BeTrue)
+				So(bindings[0].Selected, should.BeFalse)
+				So(bindings[0].Reason, should.Equal, "profile dev|test does not hold")
+				So(bindings[1].Selected, should.BeTrue)
+				So(bindings[1].Reason, should.Equal, "profile prod holds")
+			})
+		})
+
+		Convey("When I start a clone with profiles activated by the environment", func() {
+			os.Setenv(katana.ProfilesEnv, "test, other")
+			defer os.Unsetenv(katana.ProfilesEnv)
+
+			clone := injector.Clone()
+			err := clone.Start(context.Background())
+
+			Convey("Then the clone selects the bindings of the active profiles", func() {
+				var dep InterfaceDependency
+				clone.Resolve(&dep)
+
+				So(err, should.BeNil)
+				So(clone.ActiveProfiles(), should.Resemble, []string{"test", "other"})
+				So(dep.(*InterfaceDependencyImpl).Field, should.Equal, "memory")
+			})
+
+			Convey("Then the original injector is left untouched", func() {
+				So(injector.Has((*InterfaceDependency)(nil)), should.BeFalse)
+				So(injector.ConditionalBindings()[0].Evaluated, should.BeFalse)
+			})
+		})
+	})
+
+	Convey("Given I have a binding conditioned on a predicate over config", t, func() {
+		cfg := &FeatureConfig{}
+		injector := katana.New().
+			Provide(cfg).
+			When(katana.Predicate("cache enabled", func(cfg *FeatureConfig) bool {
+				return cfg.CacheEnabled
+			})).
+			ProvideSingleton(&Dependency{}, func() *Dependency {
+				return &Dependency{"cache"}
+			}).
+			ProvideNew(&DependencyA{}, func(dep *Dependency) *DependencyA {
+				return &DependencyA{dep}
+			})
+
+		Convey("When the predicate holds", func() {
+			cfg.CacheEnabled = true
+			err := injector.FreezeAndValidate()
+
+			Convey("Then the binding is selected", func() {
+				So(err, should.BeNil)
+				So(injector.Has(&Dependency{}), should.BeTrue)
+			})
+		})
+
+		Convey("When the predicate does not hold", func() {
+			err := injector.FreezeAndValidate()
+
+			Convey("Then validation reports the skipped binding", func() {
+				var skipped katana.ErrSkippedBinding
+
+				So(errors.As(err, &skipped), should.BeTrue)
+				So(skipped.Binding.Type, should.Equal, reflect.TypeOf(&Dependency{}))
+				So(skipped.Binding.Reason, should.Equal, "cache enabled does not hold")
+				So(injector.Frozen(), should.BeFalse)
+			})
+		})
+	})
+
+	Convey("Given I have a binding conditioned on a predicate that cannot be evaluated", t, func() {
+		injector := katana.New().
+			When(katana.Predicate("cache enabled", func(cfg *FeatureConfig) bool {
+				return cfg.CacheEnabled
+			})).
+			ProvideSingleton(&Dependency{}, func() *Dependency {
+				return &Dependency{}
+			})
+
+		Convey("When I start the injector", func() {
+			err := injector.Start(context.Background())
+
+			Convey("Then it fails with ErrConditionFailed", func() {
+				So(err, should.HaveSameTypeAs, katana.ErrConditionFailed{})
+				So(errors.Is(err, katana.ErrNoSuchProvider{reflect.TypeOf(&FeatureConfig{})}), should.BeTrue)
+			})
+		})
+
+		Convey("When I freeze the injector", func() {
+			r := recovered(func() { injector.Freeze() })
+
+			Convey("Then it panics with ErrConditionFailed", func() {
+				So(r, should.HaveSameTypeAs, katana.ErrConditionFailed{})
+			})
+		})
+	})
+
+	Convey("Given I have a predicate not returning a bool", t, func() {
+		r := recovered(func() { katana.Predicate("invalid", func() int { return 0 }) })
+
+		Convey("Then it panics with ErrInvalidCallable", func() {
+			So(r, should.Resemble, katana.ErrInvalidCallable{reflect.TypeOf(func() int { return 0 })})
+		})
+	})
+}
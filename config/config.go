@@ -0,0 +1,436 @@
+// Package config populates configuration structs from defaults, JSON files, environment variables
+// and command-line flags, registering them as singletons with a katana injector.
+//
+// Fields are bound through struct tags:
+//
+//	type Config struct {
+//		Port     int           `json:"port" env:"PORT" flag:"port" default:"8080"`
+//		Timeout  time.Duration `json:"timeout" env:"TIMEOUT" default:"5s"`
+//		Database Database      `json:"database"`
+//	}
+//
+//	type Database struct {
+//		URL string `json:"url" env:"DATABASE_URL" required:"true"`
+//	}
+//
+// Values given by the "default" tag are applied first, followed by the sources in the given order,
+// later sources taking precedence over earlier ones. Fields tagged with `required:"true"` must hold
+// a non zero value once every source is applied.
+//
+// Supported field types are strings, booleans, integers, floats, time.Duration, string slices
+// (comma separated) and types implementing encoding.TextUnmarshaler. Nested structs are config
+// sections, populated recursively and registered as their own injectables.
+package config
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/drborges/katana"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Source populates the fields of the config struct pointed by target
+type Source func(target reflect.Value) error
+
+// Provide registers a singleton provider for the config struct referenced by ref, e.g. &Config{},
+// populated from the given sources upon resolution. The nested sections of the config are
+// registered as singletons as well, e.g. *Database, unless their type is already registered.
+//
+//	config.Provide(injector, &Config{}, config.JSONFile("config.json"), config.Env(), config.Flags(flag.CommandLine))
+//
+// Failures loading the config panic upon resolution with ErrInvalidValue or ErrMissingFields.
+func Provide(injector *katana.Injector, ref interface{}, sources ...Source) *katana.Injector {
+	typ := reflect.TypeOf(ref)
+	if typ == nil || typ.Kind() != reflect.Ptr || typ.Elem().Kind() != reflect.Struct {
+		panic(ErrInvalidConfig{typ})
+	}
+
+	provider := reflect.MakeFunc(reflect.FuncOf(nil, []reflect.Type{typ}, false), func([]reflect.Value) []reflect.Value {
+		cfg := reflect.New(typ.Elem())
+		if err := load(cfg, sources); err != nil {
+			panic(err)
+		}
+		return []reflect.Value{cfg}
+	})
+
+	injector.ProvideSingleton(ref, provider.Interface())
+	provideSections(injector, typ)
+
+	return injector
+}
+
+// provideSections registers a singleton provider for each section of the config type cfg
+// providing a pointer to the section within the resolved config.
+func provideSections(injector *katana.Injector, cfg reflect.Type) {
+	var visit func(typ reflect.Type, index []int)
+	visit = func(typ reflect.Type, index []int) {
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if !field.IsExported() || !isSection(field.Type) {
+				continue
+			}
+
+			fieldIndex := append(append([]int(nil), index...), i)
+			section := reflect.PointerTo(field.Type)
+
+			if !injector.Has(reflect.Zero(section).Interface()) {
+				provider := reflect.MakeFunc(reflect.FuncOf([]reflect.Type{cfg}, []reflect.Type{section}, false), func(args []reflect.Value) []reflect.Value {
+					return []reflect.Value{args[0].Elem().FieldByIndex(fieldIndex).Addr()}
+				})
+				injector.ProvideSingleton(reflect.Zero(section).Interface(), provider.Interface())
+			}
+
+			visit(field.Type, fieldIndex)
+		}
+	}
+
+	visit(cfg.Elem(), nil)
+}
+
+// Load populates the config struct pointed by target with the defaults given by its tags and
+// the given sources, in order, validating its required fields.
+func Load(target interface{}, sources ...Source) error {
+	val := reflect.ValueOf(target)
+	if !val.IsValid() {
+		return ErrInvalidConfig{}
+	}
+	if val.Kind() != reflect.Ptr || val.IsNil() || val.Elem().Kind() != reflect.Struct {
+		return ErrInvalidConfig{val.Type()}
+	}
+	return load(val, sources)
+}
+
+func load(target reflect.Value, sources []Source) error {
+	if err := Defaults()(target); err != nil {
+		return err
+	}
+
+	for _, source := range sources {
+		if err := source(target); err != nil {
+			return err
+		}
+	}
+
+	return required(target)
+}
+
+// Defaults populates fields with the values given by their "default" tag. Defaults are always
+// applied before any other source.
+func Defaults() Source {
+	return tagged("default", func(name string) (string, bool) {
+		return name, true
+	})
+}
+
+// Env populates fields with the environment variables named by their "env" tag, if set
+func Env() Source {
+	return EnvFrom(os.LookupEnv)
+}
+
+// EnvFrom works like Env, except variables are looked up with the given function
+func EnvFrom(lookup func(name string) (string, bool)) Source {
+	return tagged("env", lookup)
+}
+
+// Flags populates fields with the flags of fs named by their "flag" tag, in case they were set
+// on the command line. The flags must be defined and parsed by the application.
+func Flags(fs *flag.FlagSet) Source {
+	return func(target reflect.Value) error {
+		set := make(map[string]string)
+		fs.Visit(func(f *flag.Flag) {
+			set[f.Name] = f.Value.String()
+		})
+
+		return tagged("flag", func(name string) (string, bool) {
+			value, ok := set[name]
+			return value, ok
+		})(target)
+	}
+}
+
+// JSONFile populates fields with the JSON document in the file at path, following the
+// encoding/json conventions. Fields missing from the document are left untouched.
+//
+// As with the other sources, time.Duration fields are parsed from strings such as "5s", though
+// integer nanoseconds are accepted as well.
+func JSONFile(path string) Source {
+	return func(target reflect.Value) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		var doc interface{}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		if err := decoder.Decode(&doc); err != nil {
+			return fmt.Errorf("%v: %w", path, err)
+		}
+
+		if err := durations(doc, target.Elem().Type(), "", fmt.Sprintf("json file %q", path)); err != nil {
+			return err
+		}
+
+		if data, err = json.Marshal(doc); err != nil {
+			return fmt.Errorf("%v: %w", path, err)
+		}
+
+		if err := json.Unmarshal(data, target.Interface()); err != nil {
+			return fmt.Errorf("%v: %w", path, err)
+		}
+
+		return nil
+	}
+}
+
+// durations replaces the durations given as strings within the JSON document doc decoded into
+// the struct typ by their integer nanoseconds, which is how encoding/json decodes time.Duration
+func durations(doc interface{}, typ reflect.Type, prefix, source string) error {
+	object, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || !field.IsExported() && !field.Anonymous {
+			continue
+		}
+
+		// Fields of embedded structs are promoted to the enclosing object
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			if err := durations(object, field.Type, prefix, source); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		key, found := jsonKey(object, name)
+		if !found {
+			continue
+		}
+
+		path := prefix + field.Name
+		switch {
+		case isSection(field.Type):
+			if err := durations(object[key], field.Type, path+".", source); err != nil {
+				return err
+			}
+		case field.Type == durationType:
+			raw, ok := object[key].(string)
+			if !ok {
+				continue
+			}
+			d, err := time.ParseDuration(raw)
+			if err != nil {
+				return ErrInvalidValue{Field: path, Source: source, Value: raw, Err: err}
+			}
+			object[key] = json.Number(strconv.FormatInt(int64(d), 10))
+		}
+	}
+
+	return nil
+}
+
+// jsonKey returns the key of object matching the given field name, preferring an exact match
+// over a case-insensitive one as encoding/json does
+func jsonKey(object map[string]interface{}, name string) (string, bool) {
+	if _, ok := object[name]; ok {
+		return name, true
+	}
+
+	for key := range object {
+		if strings.EqualFold(key, name) {
+			return key, true
+		}
+	}
+
+	return "", false
+}
+
+// tagged returns a source setting each field tagged with tag to the value found by lookup
+// under the tag value, if any
+func tagged(tag string, lookup func(name string) (string, bool)) Source {
+	return func(target reflect.Value) error {
+		return fields(target.Elem(), "", func(field reflect.StructField, value reflect.Value, path string) error {
+			name, ok := field.Tag.Lookup(tag)
+			if !ok || name == "" {
+				return nil
+			}
+
+			raw, found := lookup(name)
+			if !found {
+				return nil
+			}
+
+			if err := set(value, raw); err != nil {
+				return ErrInvalidValue{Field: path, Source: fmt.Sprintf("%v %q", tag, name), Value: raw, Err: err}
+			}
+
+			return nil
+		})
+	}
+}
+
+// required checks whether every field tagged as required holds a non zero value
+func required(target reflect.Value) error {
+	var missing []string
+
+	fields(target.Elem(), "", func(field reflect.StructField, value reflect.Value, path string) error {
+		if field.Tag.Get("required") == "true" && value.IsZero() {
+			missing = append(missing, path)
+		}
+		return nil
+	})
+
+	if len(missing) > 0 {
+		return ErrMissingFields{missing}
+	}
+
+	return nil
+}
+
+// fields calls fn for every exported field of the given struct value, recursing into sections.
+// Paths are the dot separated names of the fields, e.g. Database.URL.
+func fields(v reflect.Value, prefix string, fn func(field reflect.StructField, value reflect.Value, path string) error) error {
+	typ := v.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		path := prefix + field.Name
+		if isSection(field.Type) {
+			if err := fields(v.Field(i), path+".", fn); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := fn(field, v.Field(i), path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isSection returns true for nested structs holding config fields of their own
+func isSection(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Struct && !reflect.PointerTo(typ).Implements(textUnmarshalerType)
+}
+
+// set parses raw into value according to its type
+func set(value reflect.Value, raw string) error {
+	if value.Addr().Type().Implements(textUnmarshalerType) {
+		return value.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw))
+	}
+
+	if value.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		value.SetInt(int64(d))
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		value.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetFloat(f)
+	case reflect.Slice:
+		if value.Type().Elem().Kind() != reflect.String {
+			return ErrUnsupportedType{value.Type()}
+		}
+		var items []string
+		if raw != "" {
+			items = strings.Split(raw, ",")
+		}
+		value.Set(reflect.ValueOf(items).Convert(value.Type()))
+	default:
+		return ErrUnsupportedType{value.Type()}
+	}
+
+	return nil
+}
+
+type ErrInvalidConfig struct {
+	Type reflect.Type
+}
+
+func (err ErrInvalidConfig) Error() string {
+	return fmt.Sprintf("Config must be a pointer to a struct, got %v", err.Type)
+}
+
+type ErrInvalidValue struct {
+	Field  string
+	Source string
+	Value  string
+	Err    error
+}
+
+func (err ErrInvalidValue) Error() string {
+	return fmt.Sprintf("Invalid value %q for config field %v from %v: %v", err.Value, err.Field, err.Source, err.Err)
+}
+
+func (err ErrInvalidValue) Unwrap() error {
+	return err.Err
+}
+
+type ErrMissingFields struct {
+	Fields []string
+}
+
+func (err ErrMissingFields) Error() string {
+	return fmt.Sprintf("Missing required config fields: %v", strings.Join(err.Fields, ", "))
+}
+
+type ErrUnsupportedType struct {
+	Type reflect.Type
+}
+
+func (err ErrUnsupportedType) Error() string {
+	return fmt.Sprintf("Unsupported config field type: %v", err.Type)
+}
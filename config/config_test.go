@@ -0,0 +1,191 @@
+package config_test
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/drborges/katana"
+	"github.com/drborges/katana/config"
+	"github.com/smartystreets/assertions/should"
+	. "github.com/smartystreets/goconvey/convey"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type Config struct {
+	Port     int           `json:"port" env:"PORT" flag:"port" default:"8080"`
+	Debug    bool          `json:"debug" env:"DEBUG"`
+	Timeout  time.Duration `json:"timeout" env:"TIMEOUT" default:"5s"`
+	Hosts    []string      `json:"hosts" env:"HOSTS"`
+	Database Database      `json:"database"`
+}
+
+type Database struct {
+	URL  string `json:"url" env:"DATABASE_URL" required:"true"`
+	Pool Pool   `json:"pool"`
+}
+
+type Pool struct {
+	Size int `json:"size" env:"POOL_SIZE" default:"10"`
+}
+
+func env(vars map[string]string) config.Source {
+	return config.EnvFrom(func(name string) (string, bool) {
+		value, ok := vars[name]
+		return value, ok
+	})
+}
+
+func jsonFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	Convey("Given I have a config struct with defaults", t, func() {
+		var cfg Config
+
+		Convey("When I load it from the environment", func() {
+			err := config.Load(&cfg, env(map[string]string{
+				"DEBUG":        "true",
+				"HOSTS":        "a,b",
+				"DATABASE_URL": "postgres://localhost",
+				"POOL_SIZE":    "20",
+			}))
+
+			Convey("Then fields are populated from variables and defaults", func() {
+				So(err, should.BeNil)
+				So(cfg.Port, should.Equal, 8080)
+				So(cfg.Debug, should.BeTrue)
+				So(cfg.Timeout, should.Equal, 5*time.Second)
+				So(cfg.Hosts, should.Resemble, []string{"a", "b"})
+				So(cfg.Database.URL, should.Equal, "postgres://localhost")
+				So(cfg.Database.Pool.Size, should.Equal, 20)
+			})
+		})
+
+		Convey("When I load it from layered sources", func() {
+			path := jsonFile(t, `{"port": 9000, "timeout": 1000000000, "database": {"url": "postgres://file"}}`)
+			flags := flag.NewFlagSet("test", flag.ContinueOnError)
+			flags.Int("port", 0, "port")
+			flags.Parse([]string{"-port", "9002"})
+
+			err := config.Load(&cfg,
+				config.JSONFile(path),
+				env(map[string]string{"PORT": "9001", "TIMEOUT": "2s"}),
+				config.Flags(flags))
+
+			Convey("Then later sources take precedence over earlier ones", func() {
+				So(err, should.BeNil)
+				So(cfg.Port, should.Equal, 9002)
+				So(cfg.Timeout, should.Equal, 2*time.Second)
+				So(cfg.Database.URL, should.Equal, "postgres://file")
+				So(cfg.Database.Pool.Size, should.Equal, 10)
+			})
+		})
+
+		Convey("When I load it from a JSON file holding durations", func() {
+			path := jsonFile(t, `{"timeout": "1m30s", "database": {"url": "postgres://file"}}`)
+			err := config.Load(&cfg, config.JSONFile(path))
+
+			Convey("Then durations are parsed as with the other sources", func() {
+				So(err, should.BeNil)
+				So(cfg.Timeout, should.Equal, 90*time.Second)
+				So(cfg.Database.URL, should.Equal, "postgres://file")
+			})
+		})
+
+		Convey("When a JSON file holds an invalid duration", func() {
+			path := jsonFile(t, `{"timeout": "soon"}`)
+			err := config.Load(&cfg, config.JSONFile(path))
+
+			Convey("Then ErrInvalidValue is returned", func() {
+				var invalid config.ErrInvalidValue
+
+				So(errors.As(err, &invalid), should.BeTrue)
+				So(invalid.Field, should.Equal, "Timeout")
+				So(invalid.Source, should.Equal, fmt.Sprintf("json file %q", path))
+				So(invalid.Value, should.Equal, "soon")
+			})
+		})
+
+		Convey("When a required field is not set", func() {
+			err := config.Load(&cfg)
+
+			Convey("Then ErrMissingFields is returned", func() {
+				So(err, should.Resemble, config.ErrMissingFields{[]string{"Database.URL"}})
+			})
+		})
+
+		Convey("When a value cannot be parsed", func() {
+			err := config.Load(&cfg, env(map[string]string{"PORT": "http"}))
+
+			Convey("Then ErrInvalidValue is returned", func() {
+				var invalid config.ErrInvalidValue
+
+				So(errors.As(err, &invalid), should.BeTrue)
+				So(invalid.Field, should.Equal, "Port")
+				So(invalid.Source, should.Equal, `env "PORT"`)
+				So(errors.Is(err, strconv.ErrSyntax), should.BeTrue)
+			})
+		})
+
+		Convey("When I load it into a non pointer", func() {
+			err := config.Load(cfg)
+
+			Convey("Then ErrInvalidConfig is returned", func() {
+				So(err, should.HaveSameTypeAs, config.ErrInvalidConfig{})
+			})
+		})
+
+		Convey("When I load it into nil", func() {
+			err := config.Load(nil)
+
+			Convey("Then ErrInvalidConfig is returned", func() {
+				So(err, should.Resemble, config.ErrInvalidConfig{})
+			})
+		})
+	})
+}
+
+func TestProvide(t *testing.T) {
+	Convey("Given I have a config provided to an injector", t, func() {
+		injector := config.Provide(katana.New(), &Config{}, env(map[string]string{
+			"DATABASE_URL": "postgres://localhost",
+		}))
+
+		Convey("When I resolve the config and its sections", func() {
+			var cfg *Config
+			var db *Database
+			var pool *Pool
+			injector.Resolve(&cfg, &db, &pool)
+
+			Convey("Then the sections point into the config singleton", func() {
+				So(cfg.Port, should.Equal, 8080)
+				So(db, should.Equal, &cfg.Database)
+				So(pool, should.Equal, &cfg.Database.Pool)
+				So(db.URL, should.Equal, "postgres://localhost")
+			})
+		})
+	})
+
+	Convey("Given I have an invalid config provided to an injector", t, func() {
+		injector := config.Provide(katana.New(), &Config{})
+
+		Convey("When I resolve the config", func() {
+			var cfg *Config
+			err := injector.ResolveContext(context.Background(), &cfg)
+
+			Convey("Then the resolution fails with the loading error", func() {
+				So(err, should.Resemble, config.ErrMissingFields{[]string{"Database.URL"}})
+			})
+		})
+	})
+}
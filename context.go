@@ -0,0 +1,80 @@
+package katana
+
+import (
+	"context"
+	"reflect"
+)
+
+var (
+	contextType  = reflect.TypeOf((*context.Context)(nil)).Elem()
+	injectorType = reflect.TypeOf(&Injector{})
+)
+
+type injectorKey struct{}
+
+// WithInjector returns a copy of ctx holding the given injector
+func WithInjector(ctx context.Context, injector *Injector) context.Context {
+	return context.WithValue(ctx, injectorKey{}, injector)
+}
+
+// FromContext returns the injector stored in ctx by WithInjector, if any
+func FromContext(ctx context.Context) (*Injector, bool) {
+	injector, ok := ctx.Value(injectorKey{}).(*Injector)
+	return injector, ok
+}
+
+// FromCtx resolves an instance of T with the injector stored in ctx by WithInjector.
+// The resolution is bound to ctx as in Injector#ResolveContext.
+//
+// service, err := katana.FromCtx[*AccountService](req.Context())
+func FromCtx[T any](ctx context.Context) (T, error) {
+	var instance T
+
+	injector, ok := FromContext(ctx)
+	if !ok {
+		return instance, ErrNoInjector{}
+	}
+
+	err := injector.ResolveContext(ctx, &instance)
+	return instance, err
+}
+
+// ResolveContext works like Injector#Resolve, except the resolution is bound to the given context.
+//
+// Providers taking a context.Context argument receive ctx, unless the injector has an explicit
+// binding for context.Context. Before calling each provider ResolveContext checks whether ctx is
+// done, aborting the resolution with ErrResolutionAborted in that case.
+//
+// Rather than panicking, ResolveContext returns any resolution failure as an error.
+func (injector *Injector) ResolveContext(ctx context.Context, refs ...interface{}) error {
+	defer injector.bind(ctx)()
+	return injector.tryResolve(refs...)
+}
+
+// InjectContext works like Injector#Inject, except the arguments of fn are resolved as
+// in Injector#ResolveContext.
+func (injector *Injector) InjectContext(ctx context.Context, fn interface{}) (Callable, error) {
+	defer injector.bind(ctx)()
+	return injector.tryInject(fn)
+}
+
+// bind sets ctx as the context of the current call, returning a function that restores
+// the previous one. Nested calls made by providers during a parallel resolution keep the
+// context of the parallel resolution, see Injector#Parallel.
+func (injector *Injector) bind(ctx context.Context) func() {
+	if injector.running != nil {
+		return func() {}
+	}
+
+	previous := injector.ctx
+	injector.ctx = ctx
+	return func() { injector.ctx = previous }
+}
+
+// context returns the context of the current call, defaulting to context.Background()
+func (injector *Injector) context() context.Context {
+	if injector.ctx == nil {
+		return context.Background()
+	}
+	return injector.ctx
+}
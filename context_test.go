@@ -0,0 +1,122 @@
+package katana_test
+
+import (
+	"context"
+	"errors"
+	"github.com/drborges/katana"
+	"github.com/smartystreets/assertions/should"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+type ctxKey struct{}
+
+type RemoteConfig struct {
+	Source string
+}
+
+func TestInjectorResolveContext(t *testing.T) {
+	Convey("Given I have a provider depending on context.Context", t, func() {
+		injector := katana.New().
+			ProvideNew(&RemoteConfig{}, func(ctx context.Context) *RemoteConfig {
+				source, _ := ctx.Value(ctxKey{}).(string)
+				return &RemoteConfig{source}
+			}).
+			ProvideNew(&DependencyA{}, func(_ *RemoteConfig) *DependencyA {
+				return &DependencyA{}
+			})
+
+		Convey("When I resolve it with a context", func() {
+			ctx := context.WithValue(context.Background(), ctxKey{}, "consul")
+
+			var config *RemoteConfig
+			err := injector.ResolveContext(ctx, &config)
+
+			Convey("Then the provider receives the context of the call", func() {
+				So(err, should.BeNil)
+				So(config.Source, should.Equal, "consul")
+			})
+		})
+
+		Convey("When I resolve it without a context", func() {
+			var config *RemoteConfig
+			injector.Resolve(&config)
+
+			Convey("Then the provider receives a background context", func() {
+				So(config.Source, should.BeEmpty)
+			})
+		})
+
+		Convey("When I resolve it with a cancelled context", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			var dep *DependencyA
+			err := injector.ResolveContext(ctx, &dep)
+
+			Convey("Then the resolution is aborted with the context error and the path reached so far", func() {
+				So(errors.Is(err, context.Canceled), should.BeTrue)
+				So(err.Error(), should.Equal, "Resolution aborted at [*katana_test.DependencyA]: context canceled")
+				So(dep, should.BeNil)
+			})
+
+			Convey("And the injector can still resolve dependencies afterwards", func() {
+				So(injector.ResolveContext(context.Background(), &dep), should.BeNil)
+				So(dep, should.NotBeNil)
+			})
+		})
+
+		Convey("When I inject a function with a context", func() {
+			ctx := context.WithValue(context.Background(), ctxKey{}, "etcd")
+
+			callable, err := injector.InjectContext(ctx, func(ctx context.Context, config *RemoteConfig) string {
+				return ctx.Value(ctxKey{}).(string) + ":" + config.Source
+			})
+
+			Convey("Then its arguments are resolved with the context of the call", func() {
+				So(err, should.BeNil)
+				So(callable().First(), should.Equal, "etcd:etcd")
+			})
+		})
+	})
+}
+
+func TestInjectorFromContext(t *testing.T) {
+	Convey("Given I have a context holding an injector", t, func() {
+		injector := katana.New().Provide(&Dependency{Field: "scoped"})
+		ctx := katana.WithInjector(context.Background(), injector)
+
+		Convey("Then I can retrieve the injector from the context", func() {
+			found, ok := katana.FromContext(ctx)
+
+			So(ok, should.BeTrue)
+			So(found, should.Equal, injector)
+		})
+
+		Convey("Then I can resolve instances from the context", func() {
+			dep, err := katana.FromCtx[*Dependency](ctx)
+
+			So(err, should.BeNil)
+			So(dep.Field, should.Equal, "scoped")
+		})
+
+		Convey("Then resolving from a context without an injector fails", func() {
+			_, err := katana.FromCtx[*Dependency](context.Background())
+
+			So(err, should.Resemble, katana.ErrNoInjector{})
+		})
+	})
+
+	Convey("Given I have a cloned injector", t, func() {
+		injector := katana.New()
+		clone := injector.Clone()
+
+		Convey("Then *katana.Injector resolves to the injector performing the resolution", func() {
+			var resolved *katana.Injector
+			clone.Resolve(&resolved)
+
+			So(resolved, should.Equal, clone)
+			So(resolved, should.NotPointTo, injector)
+		})
+	})
+}
@@ -0,0 +1,166 @@
+// Package debug serves the live state of a katana injector over HTTP, similarly to
+// net/http/pprof, so the wiring of a running process can be inspected.
+//
+// The handler is meant to be mounted on an internal port:
+//
+//	mux := http.NewServeMux()
+//	mux.Handle("/debug/katana/", debug.Handler(injector))
+//	go http.ListenAndServe("localhost:6060", mux)
+//
+// The following pages are served under the mount path:
+//
+//	/             registered injectables, conditional bindings, instantiated singletons, dependency graph and provider stats
+//	/graph.dot    the dependency graph in the DOT format, e.g. for rendering with graphviz
+//	/stats.folded provider durations in the folded stack format, see katana.Injector#Profile
+//
+// The state is served while the injector resolves lazy singletons, though it must not be given
+// new registrations meanwhile, which is the case of application injectors whose requests are
+// handled by clones.
+package debug
+
+import (
+	"fmt"
+	"github.com/drborges/katana"
+	"html/template"
+	"io"
+	"net/http"
+	"path"
+	"reflect"
+	"strings"
+)
+
+// Handler returns an http.Handler serving the state of the given injector
+func Handler(injector *katana.Injector) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch path.Base(req.URL.Path) {
+		case "graph.dot":
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			WriteGraph(w, injector)
+		case "stats.folded":
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			injector.WriteFoldedStacks(w)
+		default:
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			if err := index.Execute(w, state(injector)); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+		}
+	})
+}
+
+// WriteGraph writes the dependency graph of the registered injectables in the DOT format.
+// Dependencies with no registered provider, such as interfaces resolved by scanning, are
+// drawn with dashed borders. Conditional bindings are listed as comments stating whether
+// they were selected, skipped bindings of unregistered types drawn with dotted borders.
+func WriteGraph(w io.Writer, injector *katana.Injector) error {
+	var b strings.Builder
+	b.WriteString("digraph katana {\n")
+	b.WriteString("\tnode [shape=box];\n")
+
+	registered := make(map[reflect.Type]bool)
+	for _, registration := range injector.Registrations() {
+		registered[registration.Type] = true
+		fmt.Fprintf(&b, "\t%q;\n", registration.Type.String())
+	}
+
+	unregistered := make(map[reflect.Type]bool)
+	for _, registration := range injector.Registrations() {
+		for _, dep := range registration.Dependencies() {
+			if !registered[dep] && !unregistered[dep] {
+				unregistered[dep] = true
+				fmt.Fprintf(&b, "\t%q [style=dashed];\n", dep.String())
+			}
+			fmt.Fprintf(&b, "\t%q -> %q;\n", registration.Type.String(), dep.String())
+		}
+	}
+
+	for _, binding := range injector.ConditionalBindings() {
+		status := "skipped"
+		if binding.Selected {
+			status = "selected"
+		}
+		fmt.Fprintf(&b, "\t// %v %v: %v\n", status, binding.Type, binding.Reason)
+
+		if !binding.Selected && !registered[binding.Type] && !unregistered[binding.Type] {
+			unregistered[binding.Type] = true
+			fmt.Fprintf(&b, "\t%q [style=dotted, xlabel=%q];\n", binding.Type.String(), binding.Reason)
+		}
+	}
+
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type page struct {
+	Registrations []katana.Registration
+	Instantiated  []reflect.Type
+	Conditionals  []katana.ConditionalBinding
+	Graph         string
+	Stats         []katana.ProviderStats
+	Profiling     bool
+}
+
+func state(injector *katana.Injector) page {
+	var graph strings.Builder
+	WriteGraph(&graph, injector)
+
+	stats := injector.Stats()
+
+	return page{
+		Registrations: injector.Registrations(),
+		Instantiated:  injector.Instantiated(),
+		Conditionals:  injector.ConditionalBindings(),
+		Graph:         graph.String(),
+		Stats:         stats,
+		Profiling:     stats != nil,
+	}
+}
+
+var index = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<title>katana</title>
+<style>
+body { font-family: sans-serif; font-size: 14px; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+pre { background: #f5f5f5; padding: 8px; }
+</style>
+</head>
+<body>
+<h1>katana</h1>
+
+<h2>Registrations</h2>
+<table>
+<tr><th>Type</th><th>Injectable</th><th>Provider</th><th>Registered at</th><th>Instantiated</th></tr>
+{{range .Registrations}}<tr><td>{{.Type}}</td><td>{{.InjectableType}}</td><td>{{.Provider}}</td><td>{{.Location}}</td><td>{{.Instantiated}}</td></tr>
+{{end}}</table>
+
+{{if .Conditionals}}<h2>Conditional Bindings</h2>
+<table>
+<tr><th>Type</th><th>Injectable</th><th>Condition</th><th>Selected</th><th>Reason</th></tr>
+{{range .Conditionals}}<tr><td>{{.Type}}</td><td>{{.InjectableType}}</td><td>{{.Condition}}</td><td>{{.Selected}}</td><td>{{.Reason}}</td></tr>
+{{end}}</table>
+{{end}}
+<h2>Instantiated Singletons</h2>
+<ol>
+{{range .Instantiated}}<li>{{.}}</li>
+{{end}}</ol>
+
+<h2>Dependency Graph</h2>
+<p><a href="graph.dot">graph.dot</a></p>
+<pre>{{.Graph}}</pre>
+
+<h2>Provider Stats</h2>
+{{if .Profiling}}<p><a href="stats.folded">stats.folded</a></p>
+<table>
+<tr><th>Type</th><th>Calls</th><th>Total</th><th>Max</th></tr>
+{{range .Stats}}<tr><td>{{.Type}}</td><td>{{.Calls}}</td><td>{{.Total}}</td><td>{{.Max}}</td></tr>
+{{end}}</table>
+{{else}}<p>Profiling is disabled, see katana.Injector#Profile.</p>
+{{end}}
+</body>
+</html>
+`))
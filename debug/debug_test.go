@@ -0,0 +1,137 @@
+package debug_test
+
+import (
+	"github.com/drborges/katana"
+	"github.com/drborges/katana/debug"
+	"github.com/smartystreets/assertions/should"
+	. "github.com/smartystreets/goconvey/convey"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type Database struct{}
+
+type Store interface {
+	Find(id string) string
+}
+
+type Service struct {
+	DB    *Database
+	Store Store
+}
+
+func injector() *katana.Injector {
+	return katana.New().
+		ProvideSingleton(&Database{}, func() *Database { return &Database{} }).
+		ProvideNew(&Service{}, func(db *Database, store Store) *Service { return &Service{db, store} })
+}
+
+func get(handler http.Handler, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func TestHandler(t *testing.T) {
+	Convey("Given I have a debug handler serving an injector", t, func() {
+		injector := injector()
+		var db *Database
+		injector.Resolve(&db)
+
+		handler := http.StripPrefix("/debug/katana", debug.Handler(injector))
+
+		Convey("When I request the index page", func() {
+			w := get(handler, "/debug/katana/")
+
+			Convey("Then the registrations and instantiated singletons are listed", func() {
+				So(w.Code, should.Equal, http.StatusOK)
+				So(w.Header().Get("Content-Type"), should.StartWith, "text/html")
+				So(w.Body.String(), should.ContainSubstring, "<td>*debug_test.Service</td><td>New Instance Dependency</td>")
+				So(w.Body.String(), should.ContainSubstring, "debug_test.go:")
+				So(w.Body.String(), should.ContainSubstring, "<li>*debug_test.Database</li>")
+				So(w.Body.String(), should.ContainSubstring, "Profiling is disabled")
+			})
+		})
+
+		Convey("When I request the dependency graph", func() {
+			w := get(handler, "/debug/katana/graph.dot")
+
+			Convey("Then the graph is rendered in the DOT format", func() {
+				So(w.Code, should.Equal, http.StatusOK)
+				So(w.Body.String(), should.StartWith, "digraph katana {\n")
+				So(w.Body.String(), should.ContainSubstring, `"*debug_test.Service" -> "*debug_test.Database";`)
+				So(w.Body.String(), should.ContainSubstring, `"debug_test.Store" [style=dashed];`)
+				So(w.Body.String(), should.ContainSubstring, `"*debug_test.Service" -> "debug_test.Store";`)
+			})
+		})
+
+		Convey("When the injector has skipped conditional bindings", func() {
+			injector := katana.New().
+				When(katana.Profile("prod")).ProvideSingleton(&Database{}, func() *Database { return &Database{} }).
+				Freeze()
+
+			handler := debug.Handler(injector)
+
+			Convey("Then the graph states why they were skipped", func() {
+				w := get(handler, "/graph.dot")
+
+				So(w.Body.String(), should.ContainSubstring, "\t// skipped *debug_test.Database: profile prod does not hold\n")
+				So(w.Body.String(), should.ContainSubstring, `"*debug_test.Database" [style=dotted, xlabel="profile prod does not hold"];`)
+			})
+
+			Convey("Then the index page lists them", func() {
+				w := get(handler, "/")
+
+				So(w.Body.String(), should.ContainSubstring, "<td>profile prod</td><td>false</td><td>profile prod does not hold</td>")
+			})
+		})
+
+		Convey("When the injector resolves singletons while its state is served", func() {
+			injector := katana.New().
+				ProvideSingleton(&Database{}, func() *Database { return &Database{} })
+			done := make(chan struct{})
+
+			go func() {
+				defer close(done)
+				for i := 0; i < 100; i++ {
+					var db *Database
+					injector.Resolve(&db)
+					injector.Close()
+				}
+			}()
+
+			handler := debug.Handler(injector)
+
+			Convey("Then the state is served consistently", func() {
+				for i := 0; i < 100; i++ {
+					So(get(handler, "/").Code, should.Equal, http.StatusOK)
+				}
+				<-done
+			})
+		})
+
+		Convey("When the injector is profiled", func() {
+			injector := katana.New().
+				Profile().
+				ProvideSingleton(&Database{}, func() *Database { return &Database{} })
+
+			var db *Database
+			injector.Resolve(&db)
+
+			handler := debug.Handler(injector)
+
+			Convey("Then provider stats are listed", func() {
+				w := get(handler, "/")
+
+				So(w.Body.String(), should.ContainSubstring, "<td>*debug_test.Database</td><td>1</td>")
+			})
+
+			Convey("Then provider stats are served as folded stacks", func() {
+				w := get(handler, "/stats.folded")
+
+				So(w.Body.String(), should.StartWith, "*debug_test.Database ")
+			})
+		})
+	})
+}
@@ -23,7 +23,7 @@ type AccountService struct {
 	Datastore *Datastore
 }
 
-func ExampleKatanaAPI() {
+func Example_katanaAPI() {
 	// Grabs a new instance of katana.Injector
 	injector := katana.New()
 
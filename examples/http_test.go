@@ -5,8 +5,8 @@ import (
 	"fmt"
 	"github.com/drborges/katana"
 	"io/ioutil"
-	"log"
 	"net/http"
+	"net/http/httptest"
 )
 
 type Database struct {
@@ -51,12 +51,13 @@ type User struct {
 	ID, Name string
 }
 
-func ExampleHTTP() {
+func Example_http() {
 	injector := katana.New().
 		ProvideNew(&Database{}, NewDatabase).
 		ProvideNew(&Renderer{}, NewRenderer)
 
-	http.HandleFunc("/users", func(w http.ResponseWriter, req *http.Request) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users", func(w http.ResponseWriter, req *http.Request) {
 		var db *Database
 		var render *Renderer
 
@@ -70,22 +71,15 @@ func ExampleHTTP() {
 		render.JSON(200, db.AllUsers())
 	})
 
-	go func() {
-		log.Fatal(http.ListenAndServe(":8080", nil))
-	}()
+	server := httptest.NewServer(mux)
+	defer server.Close()
 
-	done := make(chan bool)
-	go func() {
-		res, _ := http.Get("http://localhost:8080/users")
-		bytes, _ := ioutil.ReadAll(res.Body)
+	res, _ := http.Get(server.URL + "/users")
+	bytes, _ := ioutil.ReadAll(res.Body)
 
-		var users []*User
-		json.Unmarshal(bytes, &users)
+	var users []*User
+	json.Unmarshal(bytes, &users)
 
-		fmt.Printf("Users: %v, %v", users[0].Name, users[1].Name)
-		// Output: Users: borges, diego
-		done <- true
-	}()
-
-	<-done
+	fmt.Printf("Users: %v, %v", users[0].Name, users[1].Name)
+	// Output: Users: borges, diego
 }
@@ -0,0 +1,9 @@
+package katana
+
+// DisablePlans makes the injector look up every dependency upon each resolution,
+// as if resolution plans were not cached. Used for benchmarking.
+func (injector *Injector) DisablePlans() *Injector {
+	injector.unplanned = true
+	injector.invalidatePlans()
+	return injector
+}
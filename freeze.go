@@ -0,0 +1,109 @@
+package katana
+
+import (
+	"errors"
+	"reflect"
+)
+
+// Freeze seals the injector after bootstrap: from now on registering providers with the
+// injector panics with ErrFrozenInjector, so that request handlers cannot accidentally
+// mutate the shared dependency graph. Clones of a frozen injector are not frozen and can
+// register their own local bindings.
+//
+// Pending conditional bindings are evaluated before freezing, see Injector#When. Freeze panics
+// with ErrConditionFailed in case a condition fails to evaluate.
+func (injector *Injector) Freeze() *Injector {
+	if err := injector.evaluateConditions(); err != nil {
+		panic(err)
+	}
+
+	injector.frozen = true
+	return injector
+}
+
+// FreezeAndValidate evaluates pending conditional bindings and validates the resulting
+// dependency graph with Injector#Validate, freezing the injector in case it is valid.
+func (injector *Injector) FreezeAndValidate() error {
+	if err := injector.evaluateConditions(); err != nil {
+		return err
+	}
+
+	if err := injector.Validate(); err != nil {
+		return err
+	}
+	injector.Freeze()
+	return nil
+}
+
+// Frozen returns true in case the injector is frozen, see Injector#Freeze
+func (injector *Injector) Frozen() bool {
+	return injector.frozen
+}
+
+// Validate checks whether every registered type can be resolved without calling any provider,
+// returning the missing, ambiguous and cyclic dependencies found, if any. Dependencies missing
+// because of a skipped or pending conditional binding are reported as ErrSkippedBinding.
+func (injector *Injector) Validate() error {
+	var errs []error
+	checked := make(map[reflect.Type]bool)
+
+	for _, registration := range injector.Registrations() {
+		if err := injector.validate(registration.Type, NewTrace(), checked); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// validate checks the dependencies of the given registered type. Types already checked are
+// skipped so that each failure is reported once.
+func (injector *Injector) validate(typ reflect.Type, trace *Trace, checked map[reflect.Type]bool) error {
+	if checked[typ] {
+		return nil
+	}
+
+	err := trace.Push(typ.String())
+	defer trace.Pop()
+	if err != nil {
+		return ErrCyclicDependency{&Trace{append([]string(nil), trace.Types...)}}
+	}
+
+	fn := reflect.TypeOf(injector.injectables[typ].Provider)
+	for i := 0; i < fn.NumIn(); i++ {
+		key, err := injector.tryLookup(fn.In(i))
+		if err != nil {
+			if binding, skipped := injector.skipped(fn.In(i)); skipped {
+				err = ErrSkippedBinding{binding}
+			}
+			checked[typ] = true
+			return ErrUnresolvableDependency{err, &Trace{append([]string(nil), trace.Types...)}}
+		}
+
+		// Types resolved without a registration, such as context.Context, are always valid
+		if _, registered := injector.injectables[key]; !registered {
+			continue
+		}
+
+		if err := injector.validate(key, trace, checked); err != nil {
+			checked[typ] = true
+			return err
+		}
+	}
+
+	checked[typ] = true
+	return nil
+}
+
+// tryLookup works like Injector#lookup, but returns the lookup failure as an error rather
+// than panicking.
+func (injector *Injector) tryLookup(typ reflect.Type) (key reflect.Type, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = asError(r)
+		}
+	}()
+
+	key, _ = injector.lookup(typ)
+	return key, nil
+}
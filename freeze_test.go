@@ -0,0 +1,129 @@
+package katana_test
+
+import (
+	"context"
+	"errors"
+	"github.com/drborges/katana"
+	"github.com/smartystreets/assertions/should"
+	. "github.com/smartystreets/goconvey/convey"
+	"reflect"
+	"testing"
+)
+
+func TestInjectorFreeze(t *testing.T) {
+	Convey("Given I have a frozen injector", t, func() {
+		injector := katana.New().
+			ProvideSingleton(&Dependency{}, func() *Dependency {
+				return &Dependency{}
+			}).
+			Freeze()
+
+		Convey("When I register a provider with it", func() {
+			r := recovered(func() {
+				injector.ProvideNew(&DependencyA{}, func(dep *Dependency) *DependencyA {
+					return &DependencyA{dep}
+				})
+			})
+
+			Convey("Then it panics with ErrFrozenInjector", func() {
+				So(injector.Frozen(), should.BeTrue)
+				So(r, should.Resemble, katana.ErrFrozenInjector{reflect.TypeOf(&DependencyA{})})
+				So(injector.Has(&DependencyA{}), should.BeFalse)
+			})
+		})
+
+		Convey("When I override one of its providers", func() {
+			r := recovered(func() {
+				injector.Override(&Dependency{}, katana.TypeNew, func() *Dependency {
+					return &Dependency{}
+				})
+			})
+
+			Convey("Then it panics with ErrFrozenInjector", func() {
+				So(r, should.Resemble, katana.ErrFrozenInjector{reflect.TypeOf(&Dependency{})})
+			})
+		})
+
+		Convey("When I register a provider with a clone", func() {
+			clone := injector.Clone().
+				ProvideNew(&DependencyA{}, func(dep *Dependency) *DependencyA {
+					return &DependencyA{dep}
+				})
+
+			Convey("Then the local binding is resolved by the clone only", func() {
+				var depA *DependencyA
+				clone.Resolve(&depA)
+
+				So(clone.Frozen(), should.BeFalse)
+				So(depA.Dep, should.NotBeNil)
+				So(injector.Has(&DependencyA{}), should.BeFalse)
+			})
+		})
+	})
+}
+
+func TestInjectorValidate(t *testing.T) {
+	Convey("Given I have an injector with a valid dependency graph", t, func() {
+		injector := katana.New().
+			ProvideSingleton(&Dependency{}, func(ctx context.Context) *Dependency {
+				return &Dependency{}
+			}).
+			ProvideNew(&DependencyA{}, func(dep *Dependency) *DependencyA {
+				return &DependencyA{dep}
+			})
+
+		Convey("When I validate and freeze it", func() {
+			err := injector.FreezeAndValidate()
+
+			Convey("Then it is frozen", func() {
+				So(err, should.BeNil)
+				So(injector.Frozen(), should.BeTrue)
+			})
+		})
+	})
+
+	Convey("Given I have an injector with missing and cyclic dependencies", t, func() {
+		injector := katana.New().
+			ProvideNew(&DependencyA{}, func(dep *Dependency) *DependencyA {
+				return &DependencyA{dep}
+			}).
+			ProvideNew(&DependencyB{}, func(depA *DependencyA) *DependencyB {
+				return &DependencyB{depA}
+			}).
+			ProvideNew(&DependencyC{}, func(depD *DependencyD) *DependencyC {
+				return &DependencyC{depD}
+			}).
+			ProvideNew(&DependencyD{}, func(depC *DependencyC) *DependencyD {
+				return &DependencyD{depC}
+			})
+
+		Convey("When I validate it", func() {
+			err := injector.Validate()
+
+			Convey("Then each failure is reported once without calling any provider", func() {
+				var unresolvable katana.ErrUnresolvableDependency
+				var cyclic katana.ErrCyclicDependency
+
+				So(errors.As(err, &unresolvable), should.BeTrue)
+				So(unresolvable.Trace.Types, should.Resemble, []string{"*katana_test.DependencyA"})
+				So(errors.Is(err, katana.ErrNoSuchProvider{reflect.TypeOf(&Dependency{})}), should.BeTrue)
+				So(errors.As(err, &cyclic), should.BeTrue)
+				So(cyclic.Trace.Types, should.Resemble, []string{
+					"*katana_test.DependencyC",
+					"*katana_test.DependencyD",
+					"*katana_test.DependencyC",
+				})
+				So(len(err.(interface{ Unwrap() []error }).Unwrap()), should.Equal, 2)
+			})
+		})
+
+		Convey("When I validate and freeze it", func() {
+			err := injector.FreezeAndValidate()
+
+			Convey("Then it is not frozen", func() {
+				So(err, should.NotBeNil)
+				So(injector.Frozen(), should.BeFalse)
+			})
+		})
+	})
+}
@@ -0,0 +1,14 @@
+module github.com/drborges/katana
+
+go 1.21
+
+require (
+	github.com/smartystreets/assertions v1.0.1
+	github.com/smartystreets/goconvey v1.6.4
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/gopherjs/gopherjs v0.0.0-20181017120253-0766667cb4d1 // indirect
+	github.com/jtolds/gls v4.20.0+incompatible // indirect
+)
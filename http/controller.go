@@ -0,0 +1,52 @@
+package http
+
+import (
+	"github.com/drborges/katana"
+	"net/http"
+	"reflect"
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// Handlers maps controller method names to their http.HandlerFunc
+type Handlers map[string]http.HandlerFunc
+
+// Controller resolves an instance of the given controller type through its registered provider
+// and exposes each of its exported methods returning nothing or an error as an http.HandlerFunc.
+// The controller is resolved exactly once whereas the method arguments are resolved upon each
+// request as in Handler:
+//
+//	injector.ProvideSingleton(&UserController{}, NewUserController)
+//
+//	users := katanahttp.Controller(injector, &UserController{})
+//	http.Handle("/users", users["List"])
+func Controller(injector *katana.Injector, controller interface{}) Handlers {
+	ref := reflect.New(reflect.TypeOf(controller))
+	injector.Resolve(ref.Interface())
+
+	ctrl := ref.Elem()
+	handlers := make(Handlers)
+
+	for i := 0; i < ctrl.NumMethod(); i++ {
+		method := ctrl.Method(i)
+		if !isHandler(method.Type()) {
+			continue
+		}
+
+		handlers[ctrl.Type().Method(i).Name] = Handler(injector, method.Interface()).ServeHTTP
+	}
+
+	return handlers
+}
+
+// isHandler returns true if the given function type returns nothing or an error
+func isHandler(typ reflect.Type) bool {
+	switch typ.NumOut() {
+	case 0:
+		return true
+	case 1:
+		return typ.Out(0) == errorType
+	default:
+		return false
+	}
+}
@@ -0,0 +1,69 @@
+package http_test
+
+import (
+	"github.com/drborges/katana"
+	katanahttp "github.com/drborges/katana/http"
+	"github.com/smartystreets/assertions/should"
+	. "github.com/smartystreets/goconvey/convey"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type UserController struct {
+	DB *Database
+}
+
+func (ctrl *UserController) List(w http.ResponseWriter) {
+	w.Write([]byte(strings.Join(ctrl.DB.Users, ",")))
+}
+
+func (ctrl *UserController) Search(w http.ResponseWriter, query url.Values) error {
+	w.Write([]byte(query.Get("name")))
+	return nil
+}
+
+func (ctrl *UserController) Count() int {
+	return len(ctrl.DB.Users)
+}
+
+func TestController(t *testing.T) {
+	Convey("Given I have a controller registered as a singleton", t, func() {
+		var created int
+
+		injector := katana.New().
+			Provide(&Database{[]string{"borges", "diego"}}).
+			ProvideSingleton(&UserController{}, func(db *Database) *UserController {
+				created++
+				return &UserController{db}
+			})
+
+		Convey("When I expose the controller methods as handlers", func() {
+			handlers := katanahttp.Controller(injector, &UserController{})
+
+			Convey("Then the controller is built exactly once", func() {
+				So(created, should.Equal, 1)
+			})
+
+			Convey("Then only methods returning nothing or an error are exposed", func() {
+				So(handlers, should.ContainKey, "List")
+				So(handlers, should.ContainKey, "Search")
+				So(handlers, should.NotContainKey, "Count")
+			})
+
+			Convey("Then the method arguments are resolved per request", func() {
+				w1 := httptest.NewRecorder()
+				handlers["List"](w1, httptest.NewRequest("GET", "/users", nil))
+
+				w2 := httptest.NewRecorder()
+				handlers["Search"](w2, httptest.NewRequest("GET", "/users?name=diego", nil))
+
+				So(w1.Body.String(), should.Equal, "borges,diego")
+				So(w2.Body.String(), should.Equal, "diego")
+				So(created, should.Equal, 1)
+			})
+		})
+	})
+}
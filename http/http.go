@@ -0,0 +1,133 @@
+// Package http integrates katana with net/http by creating request scoped injectors.
+//
+// A request scoped injector is a clone of the application injector with the following
+// instances of the request being handled registered as singletons:
+//
+// http.ResponseWriter, *http.Request, context.Context, *url.URL and url.Values (the URL query)
+//
+// The request scoped injector is also stored in the request context and can be retrieved
+// with katana.FromContext.
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/drborges/katana"
+	"log/slog"
+	"net/http"
+	"reflect"
+)
+
+// StatusError is an error carrying the HTTP status code to respond with
+type StatusError struct {
+	Code int
+	Err  error
+}
+
+// Error wraps err into a StatusError with the given status code
+func Error(code int, err error) error {
+	return StatusError{code, err}
+}
+
+func (err StatusError) Error() string {
+	return err.Err.Error()
+}
+
+func (err StatusError) Unwrap() error {
+	return err.Err
+}
+
+// StatusCode returns the HTTP status code of the error
+func (err StatusError) StatusCode() int {
+	return err.Code
+}
+
+// StatusCode maps errors returned by handlers to HTTP status codes.
+//
+// Errors implementing StatusCode() int are mapped to the code they provide, any other
+// error is mapped to http.StatusInternalServerError.
+func StatusCode(err error) int {
+	var coded interface{ StatusCode() int }
+	if errors.As(err, &coded) {
+		return coded.StatusCode()
+	}
+	return http.StatusInternalServerError
+}
+
+// Scope returns a request scoped injector cloned from the given injector along with a copy
+// of the request whose context holds the scoped injector.
+func Scope(injector *katana.Injector, w http.ResponseWriter, req *http.Request) (*katana.Injector, *http.Request) {
+	scoped := injector.Clone()
+	ctx := katana.WithInjector(req.Context(), scoped)
+	req = req.WithContext(ctx)
+
+	scoped.ProvideAs((*http.ResponseWriter)(nil), w).
+		ProvideAs((*context.Context)(nil), ctx).
+		Provide(req, req.URL, req.URL.Query())
+
+	return scoped, req
+}
+
+// Middleware creates a request scoped injector for each request handled by the next handler
+func Middleware(injector *katana.Injector) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			_, req = Scope(injector, w, req)
+			next.ServeHTTP(w, req)
+		})
+	}
+}
+
+// Handler adapts the injectable function fn into an http.Handler.
+//
+// For each request, the arguments of fn are resolved by the request scoped injector created by
+// Middleware, or by a new request scoped injector cloned from the given injector in case the
+// middleware is not in place. Functions may return nothing or an error:
+//
+//	katanahttp.Handler(injector, func(r *Renderer, db *Database) error {
+//		users, err := db.AllUsers()
+//		if err != nil {
+//			return katanahttp.Error(http.StatusServiceUnavailable, err)
+//		}
+//		r.JSON(200, users)
+//		return nil
+//	})
+//
+// Returned errors are mapped to a status code by StatusCode and written as the response.
+// Failures resolving the function arguments are wiring errors rather than request errors: they
+// are logged with slog.Default and always written as http.StatusInternalServerError.
+//
+// Handler panics with ErrInvalidHandler in case fn is not a function returning nothing or an error.
+func Handler(injector *katana.Injector, fn interface{}) http.Handler {
+	if typ := reflect.TypeOf(fn); typ == nil || typ.Kind() != reflect.Func || !isHandler(typ) {
+		panic(ErrInvalidHandler{typ})
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		scoped, ok := katana.FromContext(req.Context())
+		if !ok {
+			scoped, req = Scope(injector, w, req)
+		}
+
+		callable, err := scoped.InjectContext(req.Context(), fn)
+		if err != nil {
+			slog.ErrorContext(req.Context(), "katana: cannot resolve handler arguments", "method", req.Method, "path", req.URL.Path, "err", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		if err := callable().Err(); err != nil {
+			code := StatusCode(err)
+			http.Error(w, http.StatusText(code), code)
+		}
+	})
+}
+
+type ErrInvalidHandler struct {
+	Type reflect.Type
+}
+
+func (err ErrInvalidHandler) Error() string {
+	return fmt.Sprintf("Invalid handler %v. Expected a function returning nothing or an error.", err.Type)
+}
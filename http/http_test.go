@@ -0,0 +1,144 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/drborges/katana"
+	katanahttp "github.com/drborges/katana/http"
+	"github.com/smartystreets/assertions/should"
+	. "github.com/smartystreets/goconvey/convey"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+type Database struct {
+	Users []string
+}
+
+func TestMiddleware(t *testing.T) {
+	Convey("Given I have an injector and a handler wrapped by the injection middleware", t, func() {
+		injector := katana.New()
+
+		var scoped *katana.Injector
+		handler := katanahttp.Middleware(injector)(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			scoped, _ = katana.FromContext(req.Context())
+		}))
+
+		Convey("When I handle a request", func() {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/users?name=diego", nil)
+			handler.ServeHTTP(w, req)
+
+			Convey("Then the handler finds a request scoped injector in the request context", func() {
+				So(scoped, should.NotBeNil)
+				So(scoped, should.NotPointTo, injector)
+			})
+
+			Convey("And the request scoped injector provides the request instances", func() {
+				var writer http.ResponseWriter
+				var request *http.Request
+				var ctx context.Context
+				var query url.Values
+				scoped.Resolve(&writer, &request, &ctx, &query)
+
+				So(writer, should.Equal, w)
+				So(request.URL.Path, should.Equal, "/users")
+				So(ctx, should.Equal, request.Context())
+				So(query.Get("name"), should.Equal, "diego")
+			})
+		})
+	})
+}
+
+func TestHandler(t *testing.T) {
+	Convey("Given I have an injector providing a database", t, func() {
+		injector := katana.New().Provide(&Database{[]string{"borges", "diego"}})
+
+		var logs bytes.Buffer
+		defer slog.SetDefault(slog.Default())
+		slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+
+		Convey("When I handle a request with an injectable function", func() {
+			handler := katanahttp.Handler(injector, func(w http.ResponseWriter, db *Database) {
+				w.Write([]byte(db.Users[0]))
+			})
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest("GET", "/users", nil))
+
+			Convey("Then the function arguments are injected", func() {
+				So(w.Code, should.Equal, http.StatusOK)
+				So(w.Body.String(), should.Equal, "borges")
+			})
+		})
+
+		Convey("When the function returns an error with a status code", func() {
+			handler := katanahttp.Handler(injector, func(req *http.Request) error {
+				return katanahttp.Error(http.StatusNotFound, errors.New("no such user"))
+			})
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest("GET", "/users/3", nil))
+
+			Convey("Then it responds with the error status code", func() {
+				So(w.Code, should.Equal, http.StatusNotFound)
+			})
+		})
+
+		Convey("When the function returns an arbitrary error", func() {
+			handler := katanahttp.Handler(injector, func() error {
+				return errors.New("boom")
+			})
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest("GET", "/users", nil))
+
+			Convey("Then it responds with an internal server error", func() {
+				So(w.Code, should.Equal, http.StatusInternalServerError)
+			})
+		})
+
+		Convey("When the function arguments cannot be resolved", func() {
+			handler := katanahttp.Handler(injector, func(_ *url.URL, _ *testing.T) {})
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest("GET", "/users", nil))
+
+			Convey("Then it responds with an internal server error", func() {
+				So(w.Code, should.Equal, http.StatusInternalServerError)
+			})
+
+			Convey("Then the wiring error is logged", func() {
+				So(logs.String(), should.ContainSubstring, `level=ERROR msg="katana: cannot resolve handler arguments" method=GET path=/users`)
+				So(logs.String(), should.ContainSubstring, "*testing.T")
+			})
+		})
+
+		Convey("When the function returns something other than an error", func() {
+			r := recovered(func() { katanahttp.Handler(injector, func() int { return 0 }) })
+
+			Convey("Then it panics with ErrInvalidHandler", func() {
+				So(r, should.Resemble, katanahttp.ErrInvalidHandler{reflect.TypeOf(func() int { return 0 })})
+			})
+		})
+
+		Convey("When I pass something other than a function", func() {
+			r := recovered(func() { katanahttp.Handler(injector, &Database{}) })
+
+			Convey("Then it panics with ErrInvalidHandler", func() {
+				So(r, should.Resemble, katanahttp.ErrInvalidHandler{reflect.TypeOf(&Database{})})
+			})
+		})
+	})
+}
+
+func recovered(fn func()) (r interface{}) {
+	defer func() { r = recover() }()
+	fn()
+	return nil
+}
@@ -1,8 +1,12 @@
 package katana
 
 import (
+	"context"
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
+	"sync"
 )
 
 var (
@@ -25,6 +29,17 @@ type Provider interface{}
 // Returns Output holding zero or more resulting output
 type Callable func() Output
 
+// Injection is a Callable along with the types of the arguments resolved for it
+type Injection struct {
+	Call Callable
+	// ArgumentTypes holds the types of the function arguments, the receiver first in case of
+	// method expressions
+	ArgumentTypes []reflect.Type
+	// ResolvedTypes holds, for each argument, the type of the registered injectable resolving it,
+	// which differs from the argument type when interfaces are resolved via interface scanning
+	ResolvedTypes []reflect.Type
+}
+
 // Output list of possible output results of a Callable
 type Output []interface{}
 
@@ -42,6 +57,61 @@ func (out Output) First() interface{} {
 	return out[0]
 }
 
+// Last returns the last output of a call to a Callable
+func (out Output) Last() interface{} {
+	if out.Empty() {
+		return nil
+	}
+
+	return out[len(out)-1]
+}
+
+// Err returns the last output of a call to a Callable in case it is a non nil error,
+// returns nil otherwise
+func (out Output) Err() error {
+	err, _ := out.Last().(error)
+	return err
+}
+
+// Into assigns each output of a call to a Callable to the variable pointed by the corresponding
+// pointer, skipping nil pointers. Returns ErrOutputMismatch in case the number of pointers differs
+// from the number of outputs or an output is not assignable to the corresponding variable.
+//
+// var accounts []*Account
+// var err error
+// fetchAllAccounts().Into(&accounts, &err)
+func (out Output) Into(ptrs ...interface{}) error {
+	if len(ptrs) != len(out) {
+		return ErrOutputMismatch{Index: -1, Expected: len(ptrs), Actual: len(out)}
+	}
+
+	for i, ptr := range ptrs {
+		if ptr == nil {
+			continue
+		}
+
+		val := reflect.ValueOf(ptr)
+		if val.Kind() != reflect.Ptr || val.IsNil() {
+			return ErrNoSuchPtr{val.Type()}
+		}
+
+		target := val.Elem()
+		if out[i] == nil {
+			target.Set(reflect.Zero(target.Type()))
+			continue
+		}
+
+		value := reflect.ValueOf(out[i])
+		if !value.Type().AssignableTo(target.Type()) {
+			return ErrOutputMismatch{Index: i, Type: value.Type(), Target: target.Type()}
+		}
+
+		target.Set(value)
+	}
+
+	return nil
+}
+
 // ValidateProvider validates whether or not a given provider is valid
 // Providers must be callable a.k.a functions, taking zero or more arguments
 // and returning exactly one value, the provided instance of the registered
@@ -65,6 +135,10 @@ func ValidateProvider(provider Provider) error {
 type Injectable struct {
 	Type     InjectableType
 	Provider Provider
+	// Eager is true for singletons instantiated upfront by Injector#Start
+	Eager bool
+	// callers holds the call stack of the registration, see registration.go
+	callers []uintptr
 }
 
 // Injector is katana's DI implementation driven by typed provider functions.
@@ -79,37 +153,148 @@ type Injectable struct {
 // 3. Singleton Provider: Provides the same instance upon any request. The instance dependencies are
 // resolved exactly once cached for further use.
 type Injector struct {
-	injectables map[reflect.Type]*Injectable
-	instances   map[reflect.Type]interface{}
-	trace       *Trace
+	injectables    map[reflect.Type]*Injectable
+	instances      map[reflect.Type]interface{}
+	trace          *Trace
+	scanInterfaces bool
+	// eager holds the types of eager singletons in registration order
+	eager []reflect.Type
+	// singletons holds the types of the cached singleton instances in instantiation order
+	singletons []reflect.Type
+	// inherited is the number of singletons instantiated by the injector it was cloned from
+	inherited int
+	lifecycle *Lifecycle
+	// ctx is the context of the ongoing ResolveContext or InjectContext call, if any
+	ctx context.Context
+	// observers are notified about resolutions, see observer.go
+	observers []Observer
+	// profiler accumulates provider stats when profiling, see profile.go
+	profiler *profiler
+	// frozen injectors reject registrations, see freeze.go
+	frozen bool
+	// workers, mutex, pending and running hold the parallel resolution state, see parallel.go.
+	// The mutex also guards the instance cache against introspection, see registration.go.
+	workers int
+	mutex   sync.Mutex
+	pending map[reflect.Type]*future
+	running *parallel
+	// profiles and conditionals hold the conditional bindings state, see conditional.go
+	profiles     []string
+	conditionals []*conditional
+	// slots and plans cache the lookups of target and function types, see plan.go
+	slots     map[reflect.Type]slot
+	plans     map[reflect.Type][]slot
+	unplanned bool
 }
 
 // New provides a new instance of katana's injector
 func New() *Injector {
-	return &Injector{
+	injector := &Injector{
 		injectables: make(map[reflect.Type]*Injectable),
 		instances:   make(map[reflect.Type]interface{}),
 		trace:       &Trace{},
+		lifecycle:   &Lifecycle{},
 	}
+
+	return injector.Provide(injector.lifecycle)
 }
 
 // Clone returns a thread-safe copy of the injector
 // This is particularly useful when used within web servers or any scenario where concurrency is present
+//
+// The copy has its own *Lifecycle, so that hooks appended by providers resolved through the copy,
+// such as request scoped ones, are neither accumulated nor run by the original injector.
 func (injector *Injector) Clone() *Injector {
 	newInjector := New()
+	lifecycleType := reflect.TypeOf(newInjector.lifecycle)
 
 	for t, p := range injector.injectables {
-		newInjector.injectables[t] = p
+		if t != lifecycleType {
+			newInjector.injectables[t] = p
+		}
 	}
 
+	injector.mutex.Lock()
 	for t, i := range injector.instances {
-		newInjector.instances[t] = i
+		if t != lifecycleType {
+			newInjector.instances[t] = i
+		}
+	}
+
+	for _, t := range injector.singletons {
+		if t != lifecycleType {
+			newInjector.singletons = append(newInjector.singletons, t)
+		}
 	}
+	injector.mutex.Unlock()
+
+	newInjector.scanInterfaces = injector.scanInterfaces
+	newInjector.observers = append(newInjector.observers, injector.observers...)
+	newInjector.profiler = injector.profiler
+	newInjector.profiles = injector.profiles
+	newInjector.workers = injector.workers
+	newInjector.conditionals = injector.cloneConditionals()
+	newInjector.eager = append(newInjector.eager, injector.eager...)
+	newInjector.inherited = len(newInjector.singletons)
 
 	return newInjector
 }
 
-func (injector *Injector) provide(injectable interface{}, injType InjectableType, p Provider) *Injector {
+// ScanInterfaces enables automatic interface resolution.
+//
+// By default katana only resolves interface types explicitly registered with the injector, e.g.
+// through ProvideAs. With interface scanning enabled, an interface type with no direct binding is
+// resolved by the provider of the only registered type that implements it. In case several
+// registered types implement the interface, resolution fails with ErrAmbiguousProvider.
+func (injector *Injector) ScanInterfaces() *Injector {
+	injector.scanInterfaces = true
+	injector.invalidatePlans()
+	return injector
+}
+
+// lookup finds the injectable registered for the given type, returning it along with the type
+// it is registered under, which may differ from typ when it is resolved via interface scanning.
+func (injector *Injector) lookup(typ reflect.Type) (reflect.Type, *Injectable) {
+	if injectable, registered := injector.injectables[typ]; registered {
+		return typ, injectable
+	}
+
+	// Unless explicitly registered, context.Context is resolved as the context of the current call
+	if typ == contextType {
+		return typ, &Injectable{Type: TypeNew, Provider: injector.context}
+	}
+
+	// Unless explicitly registered, *Injector is resolved as the injector itself
+	if typ == injectorType {
+		return typ, &Injectable{Type: TypeNew, Provider: func() *Injector { return injector }}
+	}
+
+	if !injector.scanInterfaces || typ.Kind() != reflect.Interface {
+		panic(ErrNoSuchProvider{typ})
+	}
+
+	var candidates []reflect.Type
+	for t := range injector.injectables {
+		if t.Implements(typ) {
+			candidates = append(candidates, t)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		panic(ErrNoSuchProvider{typ})
+	case 1:
+		return candidates[0], injector.injectables[candidates[0]]
+	default:
+		sort.Slice(candidates, func(i, j int) bool {
+			return candidates[i].String() < candidates[j].String()
+		})
+		panic(ErrAmbiguousProvider{typ, candidates})
+	}
+}
+
+// keyOf returns the type under which the given injectable is registered
+func keyOf(injectable interface{}) reflect.Type {
 	typ := reflect.TypeOf(injectable)
 
 	// If injectable is a pointer to an interface we need to work with the type
@@ -121,6 +306,18 @@ func (injector *Injector) provide(injectable interface{}, injType InjectableType
 		typ = typ.Elem()
 	}
 
+	return typ
+}
+
+func (injector *Injector) provide(injectable interface{}, injType InjectableType, p Provider) *Injector {
+	return injector.provideType(keyOf(injectable), injType, p)
+}
+
+func (injector *Injector) provideType(typ reflect.Type, injType InjectableType, p Provider) *Injector {
+	if injector.frozen {
+		panic(ErrFrozenInjector{typ})
+	}
+
 	if _, registered := injector.injectables[typ]; registered {
 		panic(ErrProviderAlreadyRegistered{typ})
 	}
@@ -129,14 +326,87 @@ func (injector *Injector) provide(injectable interface{}, injType InjectableType
 		panic(err)
 	}
 
+	if err := validateProvidedType(typ, p); err != nil {
+		panic(err)
+	}
+
 	injector.injectables[typ] = &Injectable{
 		Type:     injType,
 		Provider: p,
+		callers:  callers(),
 	}
 
+	injector.invalidatePlans()
+
 	return injector
 }
 
+// validateProvidedType checks whether instances provided by p can be registered as typ.
+// Providers declaring an interface return type satisfied by typ, such as the ones created by
+// Injector#Provide, are checked against the actual instances upon resolution instead.
+func validateProvidedType(typ reflect.Type, p Provider) error {
+	out := reflect.TypeOf(p).Out(0)
+
+	if out.AssignableTo(typ) || (out.Kind() == reflect.Interface && typ.Implements(out)) {
+		return nil
+	}
+
+	return ErrProviderTypeMismatch{typ, out}
+}
+
+// Override works like the Provide* methods, except it replaces the provider registered for
+// the given injectable, if any, evicting its cached instance.
+//
+// Note that cached singletons depending on the overridden injectable are not evicted.
+func (injector *Injector) Override(injectable interface{}, injType InjectableType, p Provider) *Injector {
+	typ := keyOf(injectable)
+
+	if injector.frozen {
+		panic(ErrFrozenInjector{typ})
+	}
+
+	if _, registered := injector.injectables[typ]; registered {
+		injector.forget(typ)
+		delete(injector.injectables, typ)
+	}
+
+	return injector.provide(injectable, injType, p)
+}
+
+// Lookup returns the injectable registered for the given type reference, which follows the same
+// convention as the Provide* methods.
+func (injector *Injector) Lookup(injectable interface{}) (Injectable, bool) {
+	inj, registered := injector.injectables[keyOf(injectable)]
+	if !registered {
+		return Injectable{}, false
+	}
+	return *inj, true
+}
+
+// forget removes the eager flag and the cached instance of the given type, if any
+func (injector *Injector) forget(typ reflect.Type) {
+	delete(injector.instances, typ)
+
+	for i, t := range injector.singletons {
+		if t == typ && i < injector.inherited {
+			injector.inherited--
+		}
+	}
+
+	injector.singletons = without(injector.singletons, typ)
+	injector.eager = without(injector.eager, typ)
+}
+
+func without(types []reflect.Type, typ reflect.Type) []reflect.Type {
+	result := make([]reflect.Type, 0, len(types))
+	for _, t := range types {
+		if t != typ {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
 // ProvideNew provides a new instance of the registered injectable with all its dependencies (if any)
 // resolved by calling their corresponding provider functions.
 // Multiple calls to this method will yield a new result provided by the registered provider function
@@ -152,6 +422,20 @@ func (injector *Injector) ProvideSingleton(injectable interface{}, p Provider) *
 	return injector.provide(injectable, TypeSingleton, p)
 }
 
+// ProvideEagerSingleton works like ProvideSingleton, except the instance is created upfront
+// by Injector#Start rather than lazily upon the first request.
+func (injector *Injector) ProvideEagerSingleton(injectable interface{}, p Provider) *Injector {
+	injector.provide(injectable, TypeSingleton, p)
+	return injector.markEager(keyOf(injectable))
+}
+
+// markEager marks the singleton registered under typ for instantiation by Injector#Start
+func (injector *Injector) markEager(typ reflect.Type) *Injector {
+	injector.injectables[typ].Eager = true
+	injector.eager = append(injector.eager, typ)
+	return injector
+}
+
 // Provide is a short hand method that allows user defined instances to be injected as singletons
 // Under the hood a singleton provider function is created for each user defined instance.
 func (injector *Injector) Provide(instances ...interface{}) *Injector {
@@ -170,9 +454,44 @@ func (injector *Injector) Provide(instances ...interface{}) *Injector {
 //
 // injector.ProvideAs((*http.ResponseWriter)(nil), w)
 func (injector *Injector) ProvideAs(injectable, instance interface{}) *Injector {
+	typ := keyOf(injectable)
+	if instance != nil && !reflect.TypeOf(instance).AssignableTo(typ) {
+		panic(ErrProviderTypeMismatch{typ, reflect.TypeOf(instance)})
+	}
+
 	return injector.ProvideSingleton(injectable, func() interface{} { return instance })
 }
 
+// ProvideFunc works like ProvideNew, except the injectable type is inferred from the return type
+// of the provider function:
+//
+// injector.ProvideFunc(NewDatastore) // registers *Datastore assuming NewDatastore returns it
+func (injector *Injector) ProvideFunc(p Provider) *Injector {
+	return injector.provideType(returnType(p), TypeNew, p)
+}
+
+// ProvideSingletonFunc works like ProvideSingleton, except the injectable type is inferred from
+// the return type of the provider function.
+func (injector *Injector) ProvideSingletonFunc(p Provider) *Injector {
+	return injector.provideType(returnType(p), TypeSingleton, p)
+}
+
+// ProvideEagerSingletonFunc works like ProvideEagerSingleton, except the injectable type is
+// inferred from the return type of the provider function.
+func (injector *Injector) ProvideEagerSingletonFunc(p Provider) *Injector {
+	typ := returnType(p)
+	injector.provideType(typ, TypeSingleton, p)
+	return injector.markEager(typ)
+}
+
+// returnType returns the return type of the given provider, panicking in case it is invalid
+func returnType(p Provider) reflect.Type {
+	if err := ValidateProvider(p); err != nil {
+		panic(err)
+	}
+	return reflect.TypeOf(p).Out(0)
+}
+
 // Resolve resolves type references into actual instances provided by their corresponding provider
 // functions if any.
 // Each reference MUST be a pointer to the requested type, even if the requested type is already a
@@ -185,7 +504,10 @@ func (injector *Injector) ProvideAs(injectable, instance interface{}) *Injector
 // var acc *Account
 // injector.Resolve(&acc)
 func (injector *Injector) Resolve(refs ...interface{}) {
-	for _, ref := range refs {
+	vals := make([]reflect.Value, len(refs))
+	slots := make([]slot, len(refs))
+
+	for i, ref := range refs {
 		val := reflect.ValueOf(ref)
 		typ := val.Type()
 
@@ -203,45 +525,120 @@ func (injector *Injector) Resolve(refs ...interface{}) {
 
 		// The type we are going to work with from this point on is what the
 		// pointer is actually pointing to.
-		typ = typ.Elem()
+		vals[i] = val.Elem()
+		slots[i] = injector.slot(typ.Elem())
+	}
 
-		// Checks whether there is a registered provider for the type reference
-		injectable, registered := injector.injectables[typ]
-		if !registered {
-			panic(ErrNoSuchProvider{typ})
-		}
+	// Resolves the type references with either cached or new instances
+	for i, inst := range injector.resolve(slots) {
+		vals[i].Set(inst)
+	}
+}
 
-		// Checks whether there is a cached instance for the type reference
-		if inst, cached := injector.instances[typ]; cached {
-			// Resolves the dependency with the cached instance
-			val.Elem().Set(reflect.ValueOf(inst))
-			continue
-		}
+// instance returns an instance of the injectable planned for the given slot, resolving
+// its dependencies -- if any -- recursively.
+func (injector *Injector) instance(s slot) reflect.Value {
+	if len(injector.observers) > 0 {
+		injector.notifyResolveStart(s.key, injector.trace.Types)
+	}
 
-		// Add to the trace the current type reference being resolved
-		// so that cyclic dependencies may be detected
-		if err := injector.trace.Push(typ.String()); err != nil {
-			panic(err)
+	// Checks whether there is a cached instance for the type reference
+	if inst, cached := injector.instances[s.key]; cached {
+		for _, observer := range injector.observers {
+			observer.OnCacheHit(s.key)
 		}
+		return valueOf(inst, s.key)
+	}
 
-		// Resolves the provider arguments -- if any -- as dependencies returning
-		// a closure with the resolved arguments injected
-		inst := injector.Inject(injectable.Provider)()[0]
-		injector.trace.Pop()
+	// Add to the trace the current type reference being resolved
+	// so that cyclic dependencies may be detected
+	if err := injector.trace.Push(s.name); err != nil {
+		panic(err)
+	}
 
-		// Resolves the type reference with the new instance
-		val.Elem().Set(reflect.ValueOf(inst))
+	// Aborts the resolution in case the context of the current call is done
+	if err := injector.context().Err(); err != nil {
+		panic(ErrResolutionAborted{err, &Trace{Types: append([]string(nil), injector.trace.Types...)}})
+	}
 
-		// Caches the instance in case the injectable is a singleton
-		if injector.injectables[typ].Type == TypeSingleton {
-			injector.instances[typ] = inst
-		}
+	// Resolves the provider arguments -- if any -- as dependencies and calls it
+	provider := reflect.ValueOf(s.injectable.Provider)
+	inst := injector.call(s.key, injector.trace.Types, provider, injector.args(provider.Type()))
+	injector.trace.Pop()
+	inst = unwrap(inst, s.key)
+
+	// Caches the instance in case the injectable is a singleton
+	if s.injectable.Type == TypeSingleton {
+		injector.mutex.Lock()
+		injector.instances[s.key] = inst.Interface()
+		injector.singletons = append(injector.singletons, s.key)
+		injector.mutex.Unlock()
+	}
+
+	return inst
+}
+
+// unwrap returns the dynamic value of instances provided as interface values. Providers may
+// declare interface return types, such as the ones created by Injector#Provide, in which case
+// the instance must be checked against the type it is registered as.
+func unwrap(inst reflect.Value, typ reflect.Type) reflect.Value {
+	if inst.Kind() != reflect.Interface {
+		return inst
+	}
+
+	inst = valueOf(inst.Interface(), typ)
+	if !inst.Type().AssignableTo(typ) {
+		panic(ErrProviderTypeMismatch{typ, inst.Type()})
 	}
+
+	return inst
+}
+
+// args resolves the arguments of the given function type
+func (injector *Injector) args(fn reflect.Type) []reflect.Value {
+	return injector.resolve(injector.plan(fn))
+}
+
+// resolve returns an instance for each of the given slots. Top level resolutions happen in
+// parallel when enabled, see parallel.go
+func (injector *Injector) resolve(slots []slot) []reflect.Value {
+	if injector.running != nil {
+		return injector.running.nested(slots)
+	}
+
+	if injector.workers > 0 && injector.trace.Empty() {
+		return injector.resolveParallel(slots)
+	}
+
+	insts := make([]reflect.Value, len(slots))
+	for i, s := range slots {
+		insts[i] = injector.instance(s)
+	}
+	return insts
+}
+
+// valueOf returns the reflect.Value of the given instance, or the zero value of typ
+// in case the instance is nil
+func valueOf(inst interface{}, typ reflect.Type) reflect.Value {
+	if inst == nil {
+		return reflect.Zero(typ)
+	}
+	return reflect.ValueOf(inst)
 }
 
 // Inject resolves and injects all arguments of the given function 'fn' returning a Callable
 // which is essentially a closure holding the resolved argument values.
+//
+// Besides plain functions, 'fn' may be a method value such as 'controller.List', or a method
+// expression such as '(*UserController).List', in which case the receiver is resolved as the
+// first argument.
 func (injector *Injector) Inject(fn interface{}) Callable {
+	return injector.Injection(fn).Call
+}
+
+// Injection works like Injector#Inject, except the returned Callable comes along with the types
+// of the arguments it was injected with.
+func (injector *Injector) Injection(fn interface{}) Injection {
 	val := reflect.ValueOf(fn)
 	typ := val.Type()
 
@@ -249,26 +646,56 @@ func (injector *Injector) Inject(fn interface{}) Callable {
 		panic(ErrNoSuchCallable{typ})
 	}
 
-	args := make([]reflect.Value, typ.NumIn())
-	for i := 0; i < typ.NumIn(); i++ {
-		argVal := reflect.New(typ.In(i))
-		arg := argVal.Interface()
-		injector.Resolve(arg)
-		args[i] = argVal.Elem()
+	slots := injector.plan(typ)
+	args := injector.resolve(slots)
+
+	injection := Injection{
+		Call: func() Output {
+			return outputOf(val.Call(args))
+		},
+		ArgumentTypes: make([]reflect.Type, len(slots)),
+		ResolvedTypes: make([]reflect.Type, len(slots)),
 	}
 
+	for i, s := range slots {
+		injection.ArgumentTypes[i] = typ.In(i)
+		injection.ResolvedTypes[i] = s.key
+	}
+
+	return injection
+}
+
+// InjectEach works like Injector#Inject, except the arguments of 'fn' are resolved upon every
+// call to the returned Callable rather than once. Arguments provided by new instance providers
+// are therefore fresh instances on each call.
+//
+// Missing providers are still detected by InjectEach itself, whereas any other resolution failure
+// happens when the Callable is called.
+func (injector *Injector) InjectEach(fn interface{}) Callable {
+	val := reflect.ValueOf(fn)
+	typ := val.Type()
+
+	if typ.Kind() != reflect.Func {
+		panic(ErrNoSuchCallable{typ})
+	}
+
+	injector.plan(typ)
+
 	callable := func() Output {
-		values := val.Call(args)
-		output := make(Output, len(values))
-		for i, val := range values {
-			output[i] = val.Interface()
-		}
-		return output
+		return outputOf(val.Call(injector.args(typ)))
 	}
 
 	return callable
 }
 
+func outputOf(values []reflect.Value) Output {
+	output := make(Output, len(values))
+	for i, val := range values {
+		output[i] = val.Interface()
+	}
+	return output
+}
+
 type ErrNoSuchPtr struct {
 	Type reflect.Type
 }
@@ -293,6 +720,14 @@ func (err ErrNoSuchCallable) Error() string {
 	return fmt.Sprintf("Cannot inject dependencies into non callable type %v", err.Type.Kind())
 }
 
+type ErrInvalidCallable struct {
+	Type reflect.Type
+}
+
+func (err ErrInvalidCallable) Error() string {
+	return fmt.Sprintf("Invalid callable function: %v. Expected a single value or a value and an error as output.", err.Type.String())
+}
+
 type ErrNoSuchProvider struct {
 	Type reflect.Type
 }
@@ -301,6 +736,39 @@ func (err ErrNoSuchProvider) Error() string {
 	return fmt.Sprintf("No providers registered for dependency type %v", err.Type)
 }
 
+type ErrAmbiguousProvider struct {
+	Type       reflect.Type
+	Candidates []reflect.Type
+}
+
+func (err ErrAmbiguousProvider) Error() string {
+	candidates := make([]string, len(err.Candidates))
+	for i, candidate := range err.Candidates {
+		candidates[i] = candidate.String()
+	}
+	return fmt.Sprintf("Multiple providers registered implementing %v: %v", err.Type, strings.Join(candidates, ", "))
+}
+
+type ErrNoInjector struct{}
+
+func (err ErrNoInjector) Error() string {
+	return "No injector found in context"
+}
+
+type ErrOutputMismatch struct {
+	// Index of the mismatching output, -1 in case the number of outputs mismatches
+	Index            int
+	Expected, Actual int
+	Type, Target     reflect.Type
+}
+
+func (err ErrOutputMismatch) Error() string {
+	if err.Index < 0 {
+		return fmt.Sprintf("Expected %v outputs, got %v", err.Expected, err.Actual)
+	}
+	return fmt.Sprintf("Cannot assign output %v of type %v to %v", err.Index, err.Type, err.Target)
+}
+
 type ErrCyclicDependency struct {
 	Trace *Trace
 }
@@ -309,6 +777,19 @@ func (err ErrCyclicDependency) Error() string {
 	return fmt.Sprintf("Cyclic dependency detected: %v", err.Trace)
 }
 
+type ErrResolutionAborted struct {
+	Err   error
+	Trace *Trace
+}
+
+func (err ErrResolutionAborted) Error() string {
+	return fmt.Sprintf("Resolution aborted at %v: %v", err.Trace, err.Err)
+}
+
+func (err ErrResolutionAborted) Unwrap() error {
+	return err.Err
+}
+
 type ErrInvalidProvider struct {
 	Type reflect.Type
 }
@@ -317,6 +798,15 @@ func (err ErrInvalidProvider) Error() string {
 	return fmt.Sprintf("Invalid provider function: %v", err.Type.String())
 }
 
+type ErrProviderTypeMismatch struct {
+	Type     reflect.Type
+	Provided reflect.Type
+}
+
+func (err ErrProviderTypeMismatch) Error() string {
+	return fmt.Sprintf("Provided type %v is not assignable to registered type %v", err.Provided, err.Type)
+}
+
 type ErrProviderAlreadyRegistered struct {
 	Type reflect.Type
 }
@@ -324,3 +814,46 @@ type ErrProviderAlreadyRegistered struct {
 func (err ErrProviderAlreadyRegistered) Error() string {
 	return fmt.Sprintf("Provider for %v already registered", err.Type.String())
 }
+
+type ErrFrozenInjector struct {
+	Type reflect.Type
+}
+
+func (err ErrFrozenInjector) Error() string {
+	return fmt.Sprintf("Cannot register provider for %v, injector is frozen", err.Type.String())
+}
+
+type ErrUnresolvableDependency struct {
+	Err   error
+	Trace *Trace
+}
+
+func (err ErrUnresolvableDependency) Error() string {
+	return fmt.Sprintf("Unresolvable dependency of %v: %v", err.Trace, err.Err)
+}
+
+func (err ErrUnresolvableDependency) Unwrap() error {
+	return err.Err
+}
+
+type ErrConditionFailed struct {
+	Type      reflect.Type
+	Condition string
+	Err       error
+}
+
+func (err ErrConditionFailed) Error() string {
+	return fmt.Sprintf("Failed to evaluate condition %v of binding for %v: %v", err.Condition, err.Type, err.Err)
+}
+
+func (err ErrConditionFailed) Unwrap() error {
+	return err.Err
+}
+
+type ErrSkippedBinding struct {
+	Binding ConditionalBinding
+}
+
+func (err ErrSkippedBinding) Error() string {
+	return fmt.Sprintf("No provider for %v, conditional binding skipped: %v", err.Binding.Type, err.Binding.Reason)
+}
@@ -1,9 +1,12 @@
 package katana_test
 
 import (
+	"context"
+	"errors"
 	"github.com/drborges/katana"
 	"github.com/smartystreets/assertions/should"
 	. "github.com/smartystreets/goconvey/convey"
+	"reflect"
 	"testing"
 )
 
@@ -37,6 +40,13 @@ type InterfaceDependencyImpl struct {
 
 func (dep *InterfaceDependencyImpl) DoStuff() {}
 
+// recovered calls fn returning the value it panics with, if any
+func recovered(fn func()) (r interface{}) {
+	defer func() { r = recover() }()
+	fn()
+	return nil
+}
+
 func TestKatanaProvide(t *testing.T) {
 	Convey("Given I have an instance of katana injector with a few value providers", t, func() {
 		depA := &Dependency{}
@@ -300,7 +310,7 @@ func TestErrProviderAlreadyRegistered(t *testing.T) {
 
 func TestErrInvalidReference(t *testing.T) {
 	Convey("Given I have a provider registered for a given dependency", t, func() {
-		injector := katana.New().ProvideNew(Dependency{}, func() *Dependency {
+		injector := katana.New().ProvideNew(&Dependency{}, func() *Dependency {
 			return &Dependency{}
 		})
 
@@ -358,5 +368,363 @@ func TestInjectorClone(t *testing.T) {
 				})
 			})
 		})
+
+		Convey("When I clone the injector after resolving its lifecycle", func() {
+			var depA *DependencyA
+			var lifecycle *katana.Lifecycle
+			injector.Resolve(&depA, &lifecycle)
+
+			newInjector := injector.Clone()
+			newInjector.Resolve(&lifecycle)
+
+			Convey("Then the new injector instantiates its own lifecycle once", func() {
+				So(newInjector.Instantiated(), should.Resemble, []reflect.Type{
+					reflect.TypeOf(&DependencyA{}),
+					reflect.TypeOf(&katana.Lifecycle{}),
+				})
+			})
+
+			Convey("Then closing the new injector leaves the inherited singletons cached", func() {
+				So(newInjector.Close(), should.BeNil)
+				So(newInjector.Instantiated(), should.Resemble, []reflect.Type{reflect.TypeOf(&DependencyA{})})
+			})
+		})
+	})
+}
+
+type OtherInterfaceDependencyImpl struct{}
+
+func (dep *OtherInterfaceDependencyImpl) DoStuff() {}
+
+func TestInjectorScanInterfaces(t *testing.T) {
+	Convey("Given I have a singleton provider of a type implementing an interface with no direct binding", t, func() {
+		injector := katana.New().ProvideSingleton(&InterfaceDependencyImpl{}, func() *InterfaceDependencyImpl {
+			return &InterfaceDependencyImpl{}
+		})
+
+		Convey("Then it fails resolving the interface when scanning is disabled", func() {
+			var dep InterfaceDependency
+			resolveInterface := func() { injector.Resolve(&dep) }
+
+			So(resolveInterface, should.Panic)
+		})
+
+		Convey("When I enable interface scanning", func() {
+			injector.ScanInterfaces()
+
+			Convey("Then the interface is resolved by the provider of its only implementation", func() {
+				var dep InterfaceDependency
+				var impl *InterfaceDependencyImpl
+				injector.Resolve(&dep, &impl)
+
+				So(dep, should.NotBeNil)
+				So(dep, should.Equal, impl)
+			})
+
+			Convey("And the setting is inherited by cloned injectors", func() {
+				var dep InterfaceDependency
+				injector.Clone().Resolve(&dep)
+
+				So(dep, should.HaveSameTypeAs, &InterfaceDependencyImpl{})
+			})
+
+			Convey("And I register another implementation of the interface", func() {
+				injector.Provide(&OtherInterfaceDependencyImpl{})
+
+				Convey("Then it fails with an ambiguous provider error listing the candidates", func() {
+					var dep InterfaceDependency
+					err := recovered(func() { injector.Resolve(&dep) })
+
+					So(err, should.Resemble, katana.ErrAmbiguousProvider{
+						Type: reflect.TypeOf((*InterfaceDependency)(nil)).Elem(),
+						Candidates: []reflect.Type{
+							reflect.TypeOf(&InterfaceDependencyImpl{}),
+							reflect.TypeOf(&OtherInterfaceDependencyImpl{}),
+						},
+					})
+				})
+			})
+		})
+	})
+}
+
+type Controller struct {
+	Dep *Dependency
+}
+
+func (ctrl *Controller) Field(dep *DependencyA) string {
+	return ctrl.Dep.Field + ":" + dep.Dep.Field
+}
+
+func TestInjectorInjectMethods(t *testing.T) {
+	Convey("Given I have a controller with a method taking injectable arguments", t, func() {
+		injector := katana.New().
+			Provide(&Dependency{Field: "dep"}).
+			ProvideSingleton(&Controller{}, func(dep *Dependency) *Controller {
+				return &Controller{&Dependency{Field: "ctrl"}}
+			}).
+			ProvideNew(&DependencyA{}, func(dep *Dependency) *DependencyA {
+				return &DependencyA{dep}
+			})
+
+		Convey("Then I can inject the arguments of a method value", func() {
+			ctrl := &Controller{&Dependency{Field: "value"}}
+
+			So(injector.Inject(ctrl.Field)().First(), should.Equal, "value:dep")
+		})
+
+		Convey("Then I can inject the receiver and arguments of a method expression", func() {
+			So(injector.Inject((*Controller).Field)().First(), should.Equal, "ctrl:dep")
+		})
+	})
+}
+
+func TestInjectorInjectEach(t *testing.T) {
+	Convey("Given I have a new instance provider", t, func() {
+		injector := katana.New().ProvideNew(&Dependency{}, func() *Dependency {
+			return &Dependency{}
+		})
+
+		fn := func(dep *Dependency) *Dependency { return dep }
+
+		Convey("When I call a Callable created with Inject multiple times", func() {
+			callable := injector.Inject(fn)
+			dep1, dep2 := callable().First(), callable().First()
+
+			Convey("Then the arguments resolved upon injection are reused", func() {
+				So(dep1, should.Equal, dep2)
+			})
+		})
+
+		Convey("When I call a Callable created with InjectEach multiple times", func() {
+			callable := injector.InjectEach(fn)
+			dep1, dep2 := callable().First(), callable().First()
+
+			Convey("Then the arguments are resolved upon each call", func() {
+				So(dep1, should.NotPointTo, dep2)
+			})
+		})
+
+		Convey("Then InjectEach fails upfront in case of missing providers", func() {
+			So(func() { injector.InjectEach(func(dep *DependencyA) {}) }, should.Panic)
+		})
+	})
+}
+
+func TestInjectorInjection(t *testing.T) {
+	Convey("Given I have an interface resolved through interface scanning", t, func() {
+		injector := katana.New().ScanInterfaces().Provide(&InterfaceDependencyImpl{}, Dependency{})
+
+		Convey("When I inject a function", func() {
+			injection := injector.Injection(func(dep InterfaceDependency, _ Dependency) InterfaceDependency {
+				return dep
+			})
+
+			Convey("Then the callable is injected with the resolved arguments", func() {
+				So(injection.Call().First(), should.Resemble, &InterfaceDependencyImpl{})
+			})
+
+			Convey("Then the argument types are the types of the function arguments", func() {
+				So(injection.ArgumentTypes, should.Resemble, []reflect.Type{
+					reflect.TypeOf((*InterfaceDependency)(nil)).Elem(),
+					reflect.TypeOf(Dependency{}),
+				})
+			})
+
+			Convey("Then the resolved types are the types of the injectables resolving them", func() {
+				So(injection.ResolvedTypes, should.Resemble, []reflect.Type{
+					reflect.TypeOf(&InterfaceDependencyImpl{}),
+					reflect.TypeOf(Dependency{}),
+				})
+			})
+		})
+	})
+}
+
+func TestOutput(t *testing.T) {
+	Convey("Given I have the output of a function returning a value and an error", t, func() {
+		err := errors.New("failure")
+		output := katana.Output{"value", err}
+
+		Convey("Then I can access its last value and error", func() {
+			So(output.Last(), should.Equal, err)
+			So(output.Err(), should.Equal, err)
+		})
+	})
+
+	Convey("Given I have the output of a function returning a nil error", t, func() {
+		output := katana.Output{"value", nil}
+
+		Convey("Then its error is nil", func() {
+			So(output.Err(), should.BeNil)
+		})
+	})
+
+	Convey("Given I have the output of a function returning a slice and an error", t, func() {
+		output := katana.Output{[]string{"a", "b"}, errors.New("failure")}
+
+		Convey("Then I can assign the outputs to typed variables", func() {
+			var values []string
+			var err error
+
+			So(output.Into(&values, &err), should.BeNil)
+			So(values, should.Resemble, []string{"a", "b"})
+			So(err.Error(), should.Equal, "failure")
+		})
+
+		Convey("Then I can skip outputs with nil pointers", func() {
+			var err error
+
+			So(output.Into(nil, &err), should.BeNil)
+			So(err, should.NotBeNil)
+		})
+
+		Convey("Then assigning an output to a variable of a different type fails", func() {
+			var values []int
+			var err error
+
+			So(output.Into(&values, &err), should.Resemble, katana.ErrOutputMismatch{
+				Index:  0,
+				Type:   reflect.TypeOf([]string{}),
+				Target: reflect.TypeOf([]int{}),
+			})
+		})
+
+		Convey("Then assigning the outputs to a different number of variables fails", func() {
+			var values []string
+
+			So(output.Into(&values), should.Resemble, katana.ErrOutputMismatch{
+				Index:    -1,
+				Expected: 1,
+				Actual:   2,
+			})
+		})
+	})
+
+	Convey("Given I have an empty output", t, func() {
+		output := katana.Output{}
+
+		Convey("Then its last value and error are nil", func() {
+			So(output.Last(), should.BeNil)
+			So(output.Err(), should.BeNil)
+		})
+	})
+}
+
+func TestInjectorOverride(t *testing.T) {
+	Convey("Given I have resolved a singleton", t, func() {
+		injector := katana.New().Provide(&Dependency{Field: "original"})
+
+		var dep *Dependency
+		injector.Resolve(&dep)
+
+		Convey("When I override its provider", func() {
+			injector.Override(&Dependency{}, katana.TypeNew, func() *Dependency {
+				return &Dependency{Field: "override"}
+			})
+
+			Convey("Then the cached instance is evicted and the new provider is used", func() {
+				injector.Resolve(&dep)
+
+				So(dep.Field, should.Equal, "override")
+			})
+
+			Convey("And the registration reflects the new provider", func() {
+				injectable, registered := injector.Lookup(&Dependency{})
+
+				So(registered, should.BeTrue)
+				So(injectable.Type, should.Equal, katana.TypeNew)
+			})
+		})
+	})
+
+	Convey("Given I have an injector with no registrations", t, func() {
+		injector := katana.New()
+
+		Convey("Then looking up a type that is not registered fails", func() {
+			_, registered := injector.Lookup((*InterfaceDependency)(nil))
+
+			So(registered, should.BeFalse)
+		})
+	})
+}
+
+func TestErrProviderTypeMismatch(t *testing.T) {
+	Convey("Given I register a provider whose return type is not assignable to the registered type", t, func() {
+		register := func() {
+			katana.New().ProvideNew(&DependencyA{}, func() *DependencyB {
+				return &DependencyB{}
+			})
+		}
+
+		Convey("Then it fails with a provider type mismatch error", func() {
+			So(recovered(register), should.Resemble, katana.ErrProviderTypeMismatch{
+				Type:     reflect.TypeOf(&DependencyA{}),
+				Provided: reflect.TypeOf(&DependencyB{}),
+			})
+		})
+	})
+
+	Convey("Given I register a provider returning an implementation of the registered interface", t, func() {
+		register := func() {
+			katana.New().ProvideNew((*InterfaceDependency)(nil), func() *InterfaceDependencyImpl {
+				return &InterfaceDependencyImpl{}
+			})
+		}
+
+		Convey("Then it is successfully registered", func() {
+			So(register, should.NotPanic)
+		})
+	})
+
+	Convey("Given I provide an instance as an interface it does not implement", t, func() {
+		register := func() {
+			katana.New().ProvideAs((*InterfaceDependency)(nil), &Dependency{})
+		}
+
+		Convey("Then it fails with a provider type mismatch error", func() {
+			So(register, should.Panic)
+		})
+	})
+
+	Convey("Given I register a provider declaring an interface return type", t, func() {
+		injector := katana.New().ProvideNew(&Dependency{}, func() interface{} {
+			return &DependencyA{}
+		})
+
+		Convey("Then resolving an instance of a different type fails", func() {
+			var dep *Dependency
+			So(recovered(func() { injector.Resolve(&dep) }), should.Resemble, katana.ErrProviderTypeMismatch{
+				Type:     reflect.TypeOf(&Dependency{}),
+				Provided: reflect.TypeOf(&DependencyA{}),
+			})
+		})
+	})
+}
+
+func TestKatanaProvideFunc(t *testing.T) {
+	Convey("Given I register providers inferring the injectable type from their return type", t, func() {
+		injector := katana.New().
+			ProvideFunc(func() *Dependency { return &Dependency{} }).
+			ProvideSingletonFunc(func(dep *Dependency) *DependencyA { return &DependencyA{dep} })
+
+		Convey("Then instances are resolved according to the type of provider", func() {
+			var dep1, dep2 *Dependency
+			var depA1, depA2 *DependencyA
+			injector.Resolve(&dep1, &dep2, &depA1, &depA2)
+
+			So(dep1, should.NotPointTo, dep2)
+			So(depA1, should.Equal, depA2)
+		})
+	})
+
+	Convey("Given I register an eager singleton provider returning an interface", t, func() {
+		injector := katana.New().
+			ProvideEagerSingletonFunc(func() InterfaceDependency { return &InterfaceDependencyImpl{} })
+
+		Convey("Then it is registered under the interface type and instantiated by Start", func() {
+			So(injector.Start(context.Background()), should.BeNil)
+			So(injector.Instantiated(), should.Resemble, []reflect.Type{reflect.TypeOf((*InterfaceDependency)(nil)).Elem()})
+		})
 	})
 }
@@ -0,0 +1,165 @@
+package katanatest
+
+import (
+	"fmt"
+	"github.com/drborges/katana"
+	"reflect"
+	"sync"
+)
+
+var (
+	fakesMutex sync.Mutex
+	fakes      = make(map[reflect.Type]reflect.Value)
+)
+
+// RegisterFake registers the adapter used to build fakes of the given interface, referenced as
+// in the katana.Injector#Provide* methods. The adapter is a function taking a *Recorder and
+// returning an implementation of the interface forwarding its method calls to the recorder.
+//
+// Go cannot implement interfaces at runtime -- reflect.StructOf does not support methods -- hence
+// the adapter, whereas recording calls and providing return values is left to the Recorder.
+// Adapters are generated for the interfaces of a package by katana-gen -fakes, which writes code
+// along the lines of:
+//
+//	type FakeStore struct{ *katanatest.Recorder }
+//
+//	func (fake FakeStore) Find(id string) (*User, error) {
+//		var user *User
+//		var err error
+//		fake.Record("Find", id).Into(&user, &err)
+//		return user, err
+//	}
+//
+//	func init() {
+//		katanatest.RegisterFake((*Store)(nil), func(r *katanatest.Recorder) Store { return FakeStore{r} })
+//	}
+func RegisterFake(iface interface{}, adapter interface{}) {
+	typ := typeOf(iface)
+	fn := reflect.ValueOf(adapter)
+
+	if typ.Kind() != reflect.Interface {
+		panic(fmt.Sprintf("katanatest: cannot register fake for non interface type %v", typ))
+	}
+
+	if fn.Kind() != reflect.Func || fn.Type().NumIn() != 1 || fn.Type().In(0) != recorderType ||
+		fn.Type().NumOut() != 1 || !fn.Type().Out(0).Implements(typ) {
+		panic(fmt.Sprintf("katanatest: invalid fake adapter %v for %v", fn.Type(), typ))
+	}
+
+	fakesMutex.Lock()
+	defer fakesMutex.Unlock()
+	fakes[typ] = fn
+}
+
+var recorderType = reflect.TypeOf(&Recorder{})
+
+// Call is a method call recorded by a Recorder
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// Recorder records the method calls made to a fake, returning either the values configured for
+// each method or the zero values of the method's return types.
+type Recorder struct {
+	Interface reflect.Type
+	mutex     sync.Mutex
+	calls     []Call
+	returns   map[string][]interface{}
+}
+
+// NewRecorder returns a Recorder for fakes of the given interface, referenced as in the
+// katana.Injector#Provide* methods.
+func NewRecorder(iface interface{}) *Recorder {
+	return &Recorder{
+		Interface: typeOf(iface),
+		returns:   make(map[string][]interface{}),
+	}
+}
+
+// Returns configures the values returned by calls to the given method
+func (r *Recorder) Returns(method string, values ...interface{}) *Recorder {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.returns[method] = values
+	return r
+}
+
+// Record records a call to the given method with the given arguments, returning the values
+// configured for the method or the zero values of its return types.
+func (r *Recorder) Record(method string, args ...interface{}) katana.Output {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.calls = append(r.calls, Call{method, args})
+
+	if values, configured := r.returns[method]; configured {
+		return katana.Output(values)
+	}
+
+	m, found := r.Interface.MethodByName(method)
+	if !found {
+		panic(fmt.Sprintf("katanatest: %v has no method %v", r.Interface, method))
+	}
+
+	output := make(katana.Output, m.Type.NumOut())
+	for i := range output {
+		output[i] = reflect.Zero(m.Type.Out(i)).Interface()
+	}
+	return output
+}
+
+// Calls returns the recorded calls to the given method, or all recorded calls in case
+// no method is given.
+func (r *Recorder) Calls(method ...string) []Call {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var calls []Call
+	for _, call := range r.calls {
+		if len(method) == 0 || call.Method == method[0] {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// FakeUnbound provides fakes for every interface with a registered fake adapter that is not bound
+// by the injector, so that interface dependencies not relevant to the test are stubbed out. The
+// recorder of each fake can be retrieved with Injector#Recorder.
+func (injector *Injector) FakeUnbound() *Injector {
+	fakesMutex.Lock()
+	defer fakesMutex.Unlock()
+
+	if injector.recorders == nil {
+		injector.recorders = make(map[reflect.Type]*Recorder)
+	}
+
+	for typ, adapter := range fakes {
+		ref := reflect.New(typ).Interface()
+		if _, bound := injector.Lookup(ref); bound {
+			continue
+		}
+
+		recorder := NewRecorder(ref)
+		fake := adapter.Call([]reflect.Value{reflect.ValueOf(recorder)})[0].Interface()
+
+		injector.recorders[typ] = recorder
+		injector.Fake(ref, fake)
+	}
+
+	return injector
+}
+
+// Recorder returns the recorder of the fake provided by Injector#FakeUnbound for the given interface,
+// failing the test in case there is no such fake.
+func (injector *Injector) Recorder(iface interface{}) *Recorder {
+	injector.t.Helper()
+
+	recorder, found := injector.recorders[typeOf(iface)]
+	if !found {
+		injector.t.Fatalf("katanatest: no fake provided for %v", typeOf(iface))
+	}
+
+	return recorder
+}
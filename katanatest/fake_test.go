@@ -0,0 +1,79 @@
+package katanatest_test
+
+import (
+	"errors"
+	"github.com/drborges/katana"
+	"github.com/drborges/katana/katanatest"
+	"github.com/smartystreets/assertions/should"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+type Mailer interface {
+	Send(to, body string) error
+}
+
+type FakeMailer struct{ *katanatest.Recorder }
+
+func (fake FakeMailer) Send(to, body string) error {
+	return fake.Record("Send", to, body).Err()
+}
+
+func init() {
+	katanatest.RegisterFake((*Mailer)(nil), func(r *katanatest.Recorder) Mailer { return FakeMailer{r} })
+	katanatest.RegisterFake((*Store)(nil), func(r *katanatest.Recorder) Store { return &FakeStore{} })
+}
+
+type Notifier struct {
+	Mailer Mailer
+	Store  Store
+}
+
+func (notifier *Notifier) Notify(id string) error {
+	return notifier.Mailer.Send(notifier.Store.Find(id), "hello")
+}
+
+func TestFakeUnbound(t *testing.T) {
+	Convey("Given I have a service depending on a bound and an unbound interface", t, func() {
+		base := katana.New().
+			ProvideSingleton((*Store)(nil), func() Store { return &PostgresStore{} }).
+			ProvideNew(&Notifier{}, func(mailer Mailer, store Store) *Notifier {
+				return &Notifier{mailer, store}
+			})
+
+		injector := katanatest.NewTestInjector(t, base).FakeUnbound()
+
+		Convey("When I resolve and use the service", func() {
+			var notifier *Notifier
+			injector.Resolve(&notifier)
+
+			err := notifier.Notify("1")
+
+			Convey("Then the unbound interface is satisfied by a fake returning zero values", func() {
+				So(err, should.BeNil)
+			})
+
+			Convey("And the bound interface is left untouched", func() {
+				So(notifier.Store, should.HaveSameTypeAs, &PostgresStore{})
+			})
+
+			Convey("And the calls made to the fake are recorded", func() {
+				So(injector.Recorder((*Mailer)(nil)).Calls("Send"), should.Resemble, []katanatest.Call{
+					{Method: "Send", Args: []interface{}{"postgres:1", "hello"}},
+				})
+			})
+		})
+
+		Convey("When I configure the values returned by the fake", func() {
+			failure := errors.New("smtp unavailable")
+			injector.Recorder((*Mailer)(nil)).Returns("Send", failure)
+
+			var notifier *Notifier
+			injector.Resolve(&notifier)
+
+			Convey("Then the configured values are returned", func() {
+				So(notifier.Notify("1"), should.Equal, failure)
+			})
+		})
+	})
+}
@@ -0,0 +1,230 @@
+// Package katanatest provides helpers for testing katana wiring and replacing dependencies with fakes.
+//
+//	func TestAccountService(t *testing.T) {
+//		injector := katanatest.NewTestInjector(t, app.Injector()).
+//			Fake((*Store)(nil), &FakeStore{})
+//
+//		var service *AccountService
+//		injector.Resolve(&service)
+//	}
+package katanatest
+
+import (
+	"context"
+	"github.com/drborges/katana"
+	"reflect"
+	"testing"
+)
+
+// Injector is a katana.Injector bound to a test. Wiring errors fail the test rather than panicking.
+//
+// The Provide* methods return the test injector, so that chained calls keep failing the test:
+//
+//	katanatest.NewTestInjector(t, base).ProvideNew(&Cache{}, NewCache).Resolve(&cache)
+type Injector struct {
+	*katana.Injector
+	t         testing.TB
+	recorders map[reflect.Type]*Recorder
+}
+
+// NewTestInjector returns an Injector bound to the given test, cloned from the given base injector
+// so that overrides do not leak into it. In case base is nil an empty injector is used instead.
+//
+// Singletons instantiated by the test injector are closed once the test finishes.
+func NewTestInjector(t testing.TB, base *katana.Injector) *Injector {
+	if base == nil {
+		base = katana.New()
+	}
+
+	injector := &Injector{Injector: base.Clone(), t: t}
+
+	t.Cleanup(func() {
+		if err := injector.Close(); err != nil {
+			t.Errorf("katanatest: closing singletons: %v", err)
+		}
+	})
+
+	return injector
+}
+
+// Fake overrides the given injectable with the given instance, which is provided as a singleton
+func (injector *Injector) Fake(injectable, instance interface{}) *Injector {
+	return injector.Override(injectable, katana.TypeSingleton, func() interface{} { return instance })
+}
+
+// Override replaces the provider of the given injectable, failing the test in case the provider is invalid
+func (injector *Injector) Override(injectable interface{}, injType katana.InjectableType, p katana.Provider) *Injector {
+	injector.t.Helper()
+	return injector.must(func() { injector.Injector.Override(injectable, injType, p) })
+}
+
+// ProvideNew works like katana.Injector#ProvideNew, failing the test in case of registration errors
+func (injector *Injector) ProvideNew(injectable interface{}, p katana.Provider) *Injector {
+	injector.t.Helper()
+	return injector.must(func() { injector.Injector.ProvideNew(injectable, p) })
+}
+
+// ProvideSingleton works like katana.Injector#ProvideSingleton, failing the test in case of registration errors
+func (injector *Injector) ProvideSingleton(injectable interface{}, p katana.Provider) *Injector {
+	injector.t.Helper()
+	return injector.must(func() { injector.Injector.ProvideSingleton(injectable, p) })
+}
+
+// ProvideEagerSingleton works like katana.Injector#ProvideEagerSingleton, failing the test in case of registration errors
+func (injector *Injector) ProvideEagerSingleton(injectable interface{}, p katana.Provider) *Injector {
+	injector.t.Helper()
+	return injector.must(func() { injector.Injector.ProvideEagerSingleton(injectable, p) })
+}
+
+// Provide works like katana.Injector#Provide, failing the test in case of registration errors
+func (injector *Injector) Provide(instances ...interface{}) *Injector {
+	injector.t.Helper()
+	return injector.must(func() { injector.Injector.Provide(instances...) })
+}
+
+// ProvideAs works like katana.Injector#ProvideAs, failing the test in case of registration errors
+func (injector *Injector) ProvideAs(injectable, instance interface{}) *Injector {
+	injector.t.Helper()
+	return injector.must(func() { injector.Injector.ProvideAs(injectable, instance) })
+}
+
+// ProvideFunc works like katana.Injector#ProvideFunc, failing the test in case of registration errors
+func (injector *Injector) ProvideFunc(p katana.Provider) *Injector {
+	injector.t.Helper()
+	return injector.must(func() { injector.Injector.ProvideFunc(p) })
+}
+
+// ProvideSingletonFunc works like katana.Injector#ProvideSingletonFunc, failing the test in case of registration errors
+func (injector *Injector) ProvideSingletonFunc(p katana.Provider) *Injector {
+	injector.t.Helper()
+	return injector.must(func() { injector.Injector.ProvideSingletonFunc(p) })
+}
+
+// ProvideEagerSingletonFunc works like katana.Injector#ProvideEagerSingletonFunc, failing the test in case of registration errors
+func (injector *Injector) ProvideEagerSingletonFunc(p katana.Provider) *Injector {
+	injector.t.Helper()
+	return injector.must(func() { injector.Injector.ProvideEagerSingletonFunc(p) })
+}
+
+// must runs the given registration, failing the test in case it panics
+func (injector *Injector) must(register func()) *Injector {
+	injector.t.Helper()
+
+	if r := recovered(register); r != nil {
+		injector.t.Fatalf("katanatest: %v", r)
+	}
+
+	return injector
+}
+
+// recovered calls fn, returning the value it panics with, if any
+func recovered(fn func()) (r interface{}) {
+	defer func() { r = recover() }()
+	fn()
+	return nil
+}
+
+// Resolve works like katana.Injector#Resolve, failing the test in case of wiring errors
+func (injector *Injector) Resolve(refs ...interface{}) {
+	injector.t.Helper()
+
+	if err := injector.ResolveContext(context.Background(), refs...); err != nil {
+		injector.t.Fatalf("katanatest: %v", err)
+	}
+}
+
+// Inject works like katana.Injector#Inject, failing the test in case of wiring errors
+func (injector *Injector) Inject(fn interface{}) katana.Callable {
+	injector.t.Helper()
+
+	callable, err := injector.InjectContext(context.Background(), fn)
+	if err != nil {
+		injector.t.Fatalf("katanatest: %v", err)
+	}
+
+	return callable
+}
+
+// AssertResolvable fails the test in case any of the given injectables cannot be resolved.
+// Injectables are referenced as in the katana.Injector#Provide* methods.
+func (injector *Injector) AssertResolvable(injectables ...interface{}) {
+	injector.t.Helper()
+
+	for _, injectable := range injectables {
+		ref := reflect.New(typeOf(injectable))
+		if err := injector.ResolveContext(context.Background(), ref.Interface()); err != nil {
+			injector.t.Errorf("katanatest: %v is not resolvable: %v", typeOf(injectable), err)
+		}
+	}
+}
+
+// AssertSingleton fails the test in case the given injectable is not registered as a singleton
+// or resolving it twice yields different instances.
+func (injector *Injector) AssertSingleton(injectable interface{}) {
+	injector.t.Helper()
+
+	typ := typeOf(injectable)
+	registration, registered := injector.Lookup(injectable)
+	if !registered {
+		injector.t.Errorf("katanatest: %v is not registered", typ)
+		return
+	}
+
+	if registration.Type != katana.TypeSingleton {
+		injector.t.Errorf("katanatest: %v is registered as %q, expected %q", typ, registration.Type, katana.TypeSingleton)
+		return
+	}
+
+	first, second := reflect.New(typ), reflect.New(typ)
+	injector.Resolve(first.Interface(), second.Interface())
+
+	if !sameInstance(first.Elem(), second.Elem()) {
+		injector.t.Errorf("katanatest: %v resolved to different instances", typ)
+	}
+}
+
+// sameInstance returns false in case the given values are references to different instances.
+// Values that are not references, such as structs, are considered to be the same instance.
+func sameInstance(a, b reflect.Value) bool {
+	if a.Kind() == reflect.Interface {
+		a, b = a.Elem(), b.Elem()
+	}
+
+	if a.Type() != b.Type() {
+		return false
+	}
+
+	switch a.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Chan, reflect.Func, reflect.Slice, reflect.UnsafePointer:
+		return a.Pointer() == b.Pointer()
+	default:
+		return true
+	}
+}
+
+// AssertProvidedBy fails the test in case the given injectable is not registered with the given provider
+func (injector *Injector) AssertProvidedBy(injectable interface{}, provider katana.Provider) {
+	injector.t.Helper()
+
+	typ := typeOf(injectable)
+	registration, registered := injector.Lookup(injectable)
+	if !registered {
+		injector.t.Errorf("katanatest: %v is not registered", typ)
+		return
+	}
+
+	expected, actual := reflect.ValueOf(provider), reflect.ValueOf(registration.Provider)
+	if expected.Kind() != reflect.Func || expected.Pointer() != actual.Pointer() {
+		injector.t.Errorf("katanatest: %v is provided by %v, expected %v", typ, actual.Type(), expected.Type())
+	}
+}
+
+// typeOf returns the type referenced by the given injectable, following the convention
+// of the katana.Injector#Provide* methods.
+func typeOf(injectable interface{}) reflect.Type {
+	typ := reflect.TypeOf(injectable)
+	if typ.Kind() == reflect.Ptr && typ.Elem().Kind() == reflect.Interface {
+		return typ.Elem()
+	}
+	return typ
+}
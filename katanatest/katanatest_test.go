@@ -0,0 +1,203 @@
+package katanatest_test
+
+import (
+	"fmt"
+	"github.com/drborges/katana"
+	"github.com/drborges/katana/katanatest"
+	"github.com/smartystreets/assertions/should"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+type Store interface {
+	Find(id string) string
+}
+
+type PostgresStore struct {
+	closed bool
+}
+
+func (store *PostgresStore) Find(id string) string { return "postgres:" + id }
+
+func (store *PostgresStore) Close() error {
+	store.closed = true
+	return nil
+}
+
+type FakeStore struct{}
+
+func (store *FakeStore) Find(id string) string { return "fake:" + id }
+
+type Service struct {
+	Store Store
+}
+
+func NewService(store Store) *Service {
+	return &Service{store}
+}
+
+// recorder is a testing.TB recording failures rather than failing the test
+type recorder struct {
+	testing.TB
+	failures []string
+	cleanups []func()
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.Errorf(format, args...)
+}
+
+func (r *recorder) Cleanup(fn func()) {
+	r.cleanups = append(r.cleanups, fn)
+}
+
+func (r *recorder) finish() {
+	for i := len(r.cleanups) - 1; i >= 0; i-- {
+		r.cleanups[i]()
+	}
+}
+
+func production() *katana.Injector {
+	return katana.New().
+		ProvideSingleton((*Store)(nil), func() Store { return &PostgresStore{} }).
+		ProvideNew(&Service{}, NewService)
+}
+
+func TestNewTestInjector(t *testing.T) {
+	Convey("Given I have a test injector cloned from a production injector", t, func() {
+		base := production()
+		r := &recorder{TB: t}
+		injector := katanatest.NewTestInjector(r, base)
+
+		Convey("When I fake one of its bindings", func() {
+			injector.Fake((*Store)(nil), &FakeStore{})
+
+			Convey("Then dependents are resolved with the fake", func() {
+				var service *Service
+				injector.Resolve(&service)
+
+				So(service.Store.Find("1"), should.Equal, "fake:1")
+			})
+
+			Convey("And the production injector is left untouched", func() {
+				var service *Service
+				base.Resolve(&service)
+
+				So(service.Store.Find("1"), should.Equal, "postgres:1")
+			})
+		})
+
+		Convey("When I chain registrations and a resolution", func() {
+			var store *FakeStore
+			injector.ProvideNew(&FakeStore{}, func() *FakeStore { return &FakeStore{} }).
+				Resolve(&store)
+
+			Convey("Then the chained calls are made on the test injector", func() {
+				So(r.failures, should.BeEmpty)
+				So(store, should.NotBeNil)
+			})
+		})
+
+		Convey("When I register a type that is already registered", func() {
+			var service *Service
+			injector.ProvideAs((*Store)(nil), &FakeStore{}).Resolve(&service)
+
+			Convey("Then the test fails rather than panicking", func() {
+				So(r.failures, should.HaveLength, 1)
+			})
+		})
+
+		Convey("When I override a binding with a provider of another type", func() {
+			chained := injector.Override(&Service{}, katana.TypeNew, func() *FakeStore { return &FakeStore{} })
+
+			Convey("Then the test fails rather than panicking", func() {
+				So(r.failures, should.HaveLength, 1)
+				So(r.failures[0], should.ContainSubstring, "katanatest:")
+				So(chained, should.Equal, injector)
+			})
+		})
+
+		Convey("When I override a binding with an invalid provider", func() {
+			injector.Override(&Service{}, katana.TypeNew, func() {})
+
+			Convey("Then the test fails rather than panicking", func() {
+				So(r.failures, should.HaveLength, 1)
+			})
+		})
+
+		Convey("When I register an invalid provider", func() {
+			chained := injector.ProvideNew(&FakeStore{}, func() {})
+
+			Convey("Then the test fails rather than panicking", func() {
+				So(r.failures, should.HaveLength, 1)
+				So(chained, should.Equal, injector)
+			})
+		})
+
+		Convey("When I register a provider with an inferred type that is invalid", func() {
+			injector.ProvideSingletonFunc(func() (*FakeStore, error) { return nil, nil })
+
+			Convey("Then the test fails rather than panicking", func() {
+				So(r.failures, should.HaveLength, 1)
+			})
+		})
+
+		Convey("When I resolve a type that is not registered", func() {
+			var dep *FakeStore
+			injector.Resolve(&dep)
+
+			Convey("Then the test fails rather than panicking", func() {
+				So(r.failures, should.HaveLength, 1)
+			})
+		})
+
+		Convey("When the test finishes", func() {
+			var store Store
+			injector.Resolve(&store)
+			r.finish()
+
+			Convey("Then the singletons instantiated by the test injector are closed", func() {
+				So(store.(*PostgresStore).closed, should.BeTrue)
+			})
+		})
+	})
+}
+
+func TestAssertions(t *testing.T) {
+	Convey("Given I have a test injector cloned from a production injector", t, func() {
+		r := &recorder{TB: t}
+		injector := katanatest.NewTestInjector(r, production())
+
+		Convey("Then asserting on its actual wiring succeeds", func() {
+			injector.AssertResolvable(&Service{}, (*Store)(nil))
+			injector.AssertSingleton((*Store)(nil))
+			injector.AssertProvidedBy(&Service{}, NewService)
+
+			So(r.failures, should.BeEmpty)
+		})
+
+		Convey("Then asserting on a type that is not resolvable fails", func() {
+			injector.AssertResolvable(&FakeStore{})
+
+			So(r.failures, should.HaveLength, 1)
+		})
+
+		Convey("Then asserting a new instance injectable is a singleton fails", func() {
+			injector.AssertSingleton(&Service{})
+
+			So(r.failures, should.HaveLength, 1)
+		})
+
+		Convey("Then asserting on a different provider fails", func() {
+			injector.AssertProvidedBy(&Service{}, func(store Store) *Service { return nil })
+
+			So(r.failures, should.HaveLength, 1)
+		})
+	})
+}
@@ -0,0 +1,173 @@
+// Package katanavet defines an analyzer reporting misuses of the katana API that would otherwise
+// only be detected at runtime, based on the static types of the arguments:
+//
+// 1. Passing non pointer references to Injector#Resolve (ErrNoSuchPtr)
+// 2. Registering providers that are not functions returning exactly one value (ErrInvalidProvider),
+// including the ones whose type is inferred, e.g. ProvideFunc(func() (*Cache, error) {...})
+// 3. Registering providers whose return type is not assignable to the registered type, e.g.
+// ProvideNew(&Cache{}, func() Cache {...}). As at runtime, providers returning an interface
+// implemented by the registered type are accepted, their instances being checked upon resolution.
+// 4. Providing instances with ProvideAs that do not implement the given interface
+package katanavet
+
+import (
+	"go/ast"
+	"go/types"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+const katanaPath = "github.com/drborges/katana"
+
+// Analyzer reports misuses of the katana API
+var Analyzer = &analysis.Analyzer{
+	Name:     "katanavet",
+	Doc:      "report misuses of the katana API detectable from the static types of the arguments",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	inspect.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(node ast.Node) {
+		call := node.(*ast.CallExpr)
+
+		// Arguments forwarded as in Resolve(refs...) are not statically known
+		if call.Ellipsis.IsValid() {
+			return
+		}
+
+		switch injectorMethod(pass, call) {
+		case "Resolve":
+			checkResolve(pass, call.Args)
+		case "ResolveContext":
+			if len(call.Args) > 0 {
+				checkResolve(pass, call.Args[1:])
+			}
+		case "ProvideNew", "ProvideSingleton", "ProvideEagerSingleton", "Override":
+			if len(call.Args) >= 2 {
+				checkProvider(pass, call.Args[0], call.Args[len(call.Args)-1])
+			}
+		case "ProvideFunc", "ProvideSingletonFunc", "ProvideEagerSingletonFunc":
+			if len(call.Args) == 1 {
+				providerResult(pass, call.Args[0])
+			}
+		case "ProvideAs":
+			if len(call.Args) == 2 {
+				checkProvideAs(pass, call.Args[0], call.Args[1])
+			}
+		}
+	})
+
+	return nil, nil
+}
+
+// injectorMethod returns the name of the katana.Injector method called, if any
+func injectorMethod(pass *analysis.Pass, call *ast.CallExpr) string {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+
+	fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != katanaPath {
+		return ""
+	}
+
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return ""
+	}
+
+	if ptr, ok := recv.Type().(*types.Pointer); ok {
+		if named, ok := ptr.Elem().(*types.Named); ok && named.Obj().Name() == "Injector" {
+			return fn.Name()
+		}
+	}
+
+	return ""
+}
+
+// checkResolve reports references that are statically known not to be pointers
+func checkResolve(pass *analysis.Pass, refs []ast.Expr) {
+	for _, ref := range refs {
+		typ := pass.TypesInfo.TypeOf(ref)
+		if typ == nil || types.IsInterface(typ) {
+			continue
+		}
+
+		if _, ok := typ.Underlying().(*types.Pointer); !ok {
+			pass.Reportf(ref.Pos(), "cannot resolve %v: expected a pointer to a variable", typ)
+		}
+	}
+}
+
+// checkProvider reports invalid providers and providers whose return type is not assignable
+// to the registered type
+func checkProvider(pass *analysis.Pass, injectable, provider ast.Expr) {
+	key := keyOf(pass.TypesInfo.TypeOf(injectable))
+	result := providerResult(pass, provider)
+	if key == nil || result == nil || types.AssignableTo(result, key) {
+		return
+	}
+
+	// Providers returning an interface implemented by the registered type, such as the ones
+	// created by Injector#Provide, are checked upon resolution
+	if iface, ok := result.Underlying().(*types.Interface); ok && types.Implements(key, iface) {
+		return
+	}
+
+	pass.Reportf(provider.Pos(), "provider returns %v, which is not assignable to the registered type %v", result, key)
+}
+
+// providerResult returns the type of the value returned by the given provider, reporting the
+// provider if it is not a function returning exactly one value. Returns nil if the provider is
+// invalid or its type is not statically known.
+func providerResult(pass *analysis.Pass, provider ast.Expr) types.Type {
+	typ := pass.TypesInfo.TypeOf(provider)
+	if typ == nil || types.IsInterface(typ) {
+		return nil
+	}
+
+	sig, ok := typ.Underlying().(*types.Signature)
+	if !ok {
+		pass.Reportf(provider.Pos(), "invalid provider: %v is not a function", typ)
+		return nil
+	}
+
+	if sig.Results().Len() != 1 {
+		pass.Reportf(provider.Pos(), "invalid provider: %v must return exactly one value", typ)
+		return nil
+	}
+
+	return sig.Results().At(0).Type()
+}
+
+// checkProvideAs reports instances that do not implement the interface they are provided as
+func checkProvideAs(pass *analysis.Pass, injectable, instance ast.Expr) {
+	key := keyOf(pass.TypesInfo.TypeOf(injectable))
+	typ := pass.TypesInfo.TypeOf(instance)
+	if key == nil || typ == nil || !types.IsInterface(key) || types.IsInterface(typ) {
+		return
+	}
+
+	if !types.AssignableTo(typ, key) {
+		pass.Reportf(instance.Pos(), "%v does not implement %v", typ, key)
+	}
+}
+
+// keyOf returns the type under which an injectable of the given type is registered, following
+// the pointer to interface convention. Returns nil in case the type is not statically known.
+func keyOf(typ types.Type) types.Type {
+	if typ == nil || types.IsInterface(typ) {
+		return nil
+	}
+
+	if ptr, ok := typ.(*types.Pointer); ok && types.IsInterface(ptr.Elem()) {
+		return ptr.Elem()
+	}
+
+	return typ
+}
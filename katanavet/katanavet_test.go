@@ -0,0 +1,11 @@
+package katanavet_test
+
+import (
+	"github.com/drborges/katana/katanavet"
+	"golang.org/x/tools/go/analysis/analysistest"
+	"testing"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), katanavet.Analyzer, "a")
+}
@@ -0,0 +1,66 @@
+package a
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/drborges/katana"
+)
+
+type Cache struct{}
+
+type Store interface {
+	Find(id string) string
+}
+
+type MemoryStore struct{}
+
+func (store *MemoryStore) Find(id string) string { return id }
+
+func resolve(injector *katana.Injector, ref interface{}, refs []interface{}) {
+	var cache *Cache
+	injector.Resolve(&cache, cache)
+	injector.Resolve(Cache{}) // want `cannot resolve a.Cache: expected a pointer to a variable`
+	injector.Resolve(ref)
+	injector.Resolve(refs...)
+}
+
+func resolveContext(ctx context.Context, injector *katana.Injector) {
+	var cache *Cache
+	injector.ResolveContext(ctx, &cache)
+	injector.ResolveContext(ctx, Cache{}) // want `cannot resolve a.Cache: expected a pointer to a variable`
+}
+
+func provide(injector *katana.Injector, provider katana.Provider) {
+	injector.ProvideNew(&Cache{}, func() *Cache { return &Cache{} })
+	injector.ProvideNew(&Cache{}, func() Cache { return Cache{} })                  // want `provider returns a.Cache, which is not assignable to the registered type \*a.Cache`
+	injector.ProvideNew(&Cache{}, func() {})                                        // want `invalid provider: func\(\) must return exactly one value`
+	injector.ProvideSingleton(&Cache{}, func() (*Cache, error) { return nil, nil }) // want `invalid provider: func\(\) \(\*a.Cache, error\) must return exactly one value`
+	injector.ProvideSingleton(&Cache{}, Cache{})                                    // want `invalid provider: a.Cache is not a function`
+	injector.ProvideSingleton((*Store)(nil), func() *MemoryStore { return &MemoryStore{} })
+	injector.ProvideSingleton((*Store)(nil), func() *Cache { return &Cache{} }) // want `provider returns \*a.Cache, which is not assignable to the registered type a.Store`
+	injector.ProvideSingleton(&Cache{}, provider)
+	injector.ProvideSingleton(&Cache{}, func() interface{} { return &Cache{} })
+	injector.ProvideSingleton((*Store)(nil), func() interface{} { return &MemoryStore{} })
+	injector.ProvideSingleton(&Cache{}, func() fmt.Stringer { return nil }) // want `provider returns fmt.Stringer, which is not assignable to the registered type \*a.Cache`
+	injector.ProvideEagerSingleton(&Cache{}, func() *Cache { return &Cache{} })
+	injector.ProvideEagerSingleton(&Cache{}, func() Cache { return Cache{} }) // want `provider returns a.Cache, which is not assignable to the registered type \*a.Cache`
+	injector.Override(&Cache{}, katana.TypeNew, func() *Cache { return &Cache{} })
+	injector.Override(&Cache{}, katana.TypeSingleton, Cache{}) // want `invalid provider: a.Cache is not a function`
+}
+
+func provideFunc(injector *katana.Injector, provider katana.Provider) {
+	injector.ProvideFunc(func() *Cache { return &Cache{} })
+	injector.ProvideFunc(func() (*Cache, error) { return nil, nil }) // want `invalid provider: func\(\) \(\*a.Cache, error\) must return exactly one value`
+	injector.ProvideSingletonFunc(func() {})                         // want `invalid provider: func\(\) must return exactly one value`
+	injector.ProvideSingletonFunc(func() Store { return &MemoryStore{} })
+	injector.ProvideEagerSingletonFunc(Cache{}) // want `invalid provider: a.Cache is not a function`
+	injector.ProvideEagerSingletonFunc(provider)
+}
+
+func provideAs(injector *katana.Injector, instance interface{}) {
+	injector.ProvideAs((*Store)(nil), &MemoryStore{})
+	injector.ProvideAs((*Store)(nil), MemoryStore{}) // want `a.MemoryStore does not implement a.Store`
+	injector.ProvideAs((*Store)(nil), &Cache{})      // want `\*a.Cache does not implement a.Store`
+	injector.ProvideAs((*Store)(nil), instance)
+}
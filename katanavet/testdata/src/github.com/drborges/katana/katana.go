@@ -0,0 +1,43 @@
+// Package katana is a stub of the katana API used by the analyzer tests
+package katana
+
+import "context"
+
+type Provider interface{}
+
+type InjectableType string
+
+const (
+	TypeNew       = InjectableType("TypeNew")
+	TypeSingleton = InjectableType("TypeSingleton")
+)
+
+type Injector struct{}
+
+func New() *Injector { return &Injector{} }
+
+func (injector *Injector) Resolve(refs ...interface{}) {}
+
+func (injector *Injector) ResolveContext(ctx context.Context, refs ...interface{}) error { return nil }
+
+func (injector *Injector) ProvideNew(injectable interface{}, p Provider) *Injector { return injector }
+
+func (injector *Injector) ProvideSingleton(injectable interface{}, p Provider) *Injector {
+	return injector
+}
+
+func (injector *Injector) ProvideEagerSingleton(injectable interface{}, p Provider) *Injector {
+	return injector
+}
+
+func (injector *Injector) Override(injectable interface{}, injType InjectableType, p Provider) *Injector {
+	return injector
+}
+
+func (injector *Injector) ProvideFunc(p Provider) *Injector { return injector }
+
+func (injector *Injector) ProvideSingletonFunc(p Provider) *Injector { return injector }
+
+func (injector *Injector) ProvideEagerSingletonFunc(p Provider) *Injector { return injector }
+
+func (injector *Injector) ProvideAs(injectable, instance interface{}) *Injector { return injector }
@@ -0,0 +1,116 @@
+package katana
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// DefaultHookTimeout is how long a lifecycle hook is allowed to run when its Timeout is not set
+var DefaultHookTimeout = 15 * time.Second
+
+// Hook is a pair of callbacks run by Injector#Run when the application starts and stops.
+// Either callback may be nil.
+type Hook struct {
+	OnStart func(context.Context) error
+	OnStop  func(context.Context) error
+	// Timeout bounds the execution of each callback. Defaults to DefaultHookTimeout.
+	Timeout time.Duration
+}
+
+// Lifecycle holds the hooks of long-running components such as servers and queue consumers.
+//
+// Every injector provides a *Lifecycle singleton that providers may depend on in order to
+// append hooks for the instances they create:
+//
+//	injector.ProvideEagerSingleton(&Server{}, func(lc *katana.Lifecycle) *Server {
+//		srv := &Server{}
+//		lc.Append(katana.Hook{OnStart: srv.Start, OnStop: srv.Stop})
+//		return srv
+//	})
+//
+// Since dependencies are created before their dependents, hooks are appended in dependency order.
+type Lifecycle struct {
+	mutex sync.Mutex
+	hooks []Hook
+}
+
+// Append registers the given hook
+func (lc *Lifecycle) Append(hook Hook) {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+	lc.hooks = append(lc.hooks, hook)
+}
+
+// Hooks returns a copy of the registered hooks in the order they were appended
+func (lc *Lifecycle) Hooks() []Hook {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+	return append([]Hook(nil), lc.hooks...)
+}
+
+// Run starts the injector by instantiating its eager singletons and running the lifecycle start
+// hooks in dependency order. It then blocks until ctx is done or the process receives an interrupt
+// or termination signal, and finally runs the stop hooks of the started components in reverse order.
+//
+// In case a start hook fails the components started so far are stopped and the start error is
+// returned. Otherwise the errors returned by the stop hooks, if any, are returned.
+func (injector *Injector) Run(ctx context.Context) error {
+	if err := injector.Start(ctx); err != nil {
+		return err
+	}
+
+	hooks := injector.lifecycle.Hooks()
+
+	for i, hook := range hooks {
+		if err := hook.run(ctx, hook.OnStart); err != nil {
+			return errors.Join(err, stop(hooks[:i]))
+		}
+	}
+
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	<-ctx.Done()
+
+	return stop(hooks)
+}
+
+// stop runs the stop callbacks of the given hooks in reverse order
+func stop(hooks []Hook) error {
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := hooks[i].run(context.Background(), hooks[i].OnStop); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// run calls fn with a context bound to the hook timeout, giving up on it once the timeout expires
+func (hook Hook) run(ctx context.Context, fn func(context.Context) error) error {
+	if fn == nil {
+		return nil
+	}
+
+	timeout := hook.Timeout
+	if timeout <= 0 {
+		timeout = DefaultHookTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- fn(ctx) }()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
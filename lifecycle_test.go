@@ -0,0 +1,135 @@
+package katana_test
+
+import (
+	"context"
+	"errors"
+	"github.com/drborges/katana"
+	"github.com/smartystreets/assertions/should"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+	"time"
+)
+
+type Server struct{}
+
+type Consumer struct {
+	Server *Server
+}
+
+func TestInjectorRun(t *testing.T) {
+	Convey("Given I have eager components appending lifecycle hooks", t, func() {
+		var events []string
+
+		hook := func(name string) katana.Hook {
+			return katana.Hook{
+				OnStart: func(context.Context) error {
+					events = append(events, "start "+name)
+					return nil
+				},
+				OnStop: func(context.Context) error {
+					events = append(events, "stop "+name)
+					return nil
+				},
+			}
+		}
+
+		injector := katana.New().
+			ProvideEagerSingleton(&Consumer{}, func(lc *katana.Lifecycle, srv *Server) *Consumer {
+				lc.Append(hook("consumer"))
+				return &Consumer{srv}
+			}).
+			ProvideSingleton(&Server{}, func(lc *katana.Lifecycle) *Server {
+				lc.Append(hook("server"))
+				return &Server{}
+			})
+
+		Convey("When I run the injector until its context is cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(10*time.Millisecond, cancel)
+
+			err := injector.Run(ctx)
+
+			Convey("Then start hooks run in dependency order and stop hooks in reverse", func() {
+				So(err, should.BeNil)
+				So(events, should.Resemble, []string{
+					"start server",
+					"start consumer",
+					"stop consumer",
+					"stop server",
+				})
+			})
+		})
+
+		Convey("When a start hook fails", func() {
+			failure := errors.New("cannot bind port")
+			injector.ProvideEagerSingleton(&Repository{}, func(lc *katana.Lifecycle, _ *Consumer) *Repository {
+				lc.Append(katana.Hook{OnStart: func(context.Context) error { return failure }})
+				return &Repository{}
+			})
+
+			err := injector.Run(context.Background())
+
+			Convey("Then the components started so far are stopped and the error is returned", func() {
+				So(errors.Is(err, failure), should.BeTrue)
+				So(events, should.Resemble, []string{
+					"start server",
+					"start consumer",
+					"stop consumer",
+					"stop server",
+				})
+			})
+		})
+	})
+
+	Convey("Given I have a stop hook that exceeds its timeout", t, func() {
+		injector := katana.New().ProvideEagerSingleton(&Server{}, func(lc *katana.Lifecycle) *Server {
+			lc.Append(katana.Hook{
+				OnStop: func(context.Context) error {
+					time.Sleep(time.Second)
+					return nil
+				},
+				Timeout: 10 * time.Millisecond,
+			})
+			return &Server{}
+		})
+
+		Convey("Then running the injector returns a deadline exceeded error", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(10*time.Millisecond, cancel)
+
+			So(errors.Is(injector.Run(ctx), context.DeadlineExceeded), should.BeTrue)
+		})
+	})
+}
+
+func TestInjectorCloneLifecycle(t *testing.T) {
+	Convey("Given I have an injector with a request scoped provider appending lifecycle hooks", t, func() {
+		injector := katana.New().ProvideNew(&Server{}, func(lc *katana.Lifecycle) *Server {
+			lc.Append(katana.Hook{})
+			return &Server{}
+		})
+
+		var lifecycle *katana.Lifecycle
+		injector.Resolve(&lifecycle)
+
+		Convey("When I resolve it through a few clones", func() {
+			var clones []*katana.Lifecycle
+			for i := 0; i < 3; i++ {
+				var srv *Server
+				var lc *katana.Lifecycle
+				injector.Clone().Resolve(&srv, &lc)
+				clones = append(clones, lc)
+			}
+
+			Convey("Then the hooks are appended to the lifecycle of each clone", func() {
+				So(clones[0], should.NotPointTo, lifecycle)
+				So(clones[1], should.NotPointTo, clones[0])
+				So(clones[0].Hooks(), should.HaveLength, 1)
+			})
+
+			Convey("Then the original injector does not accumulate them", func() {
+				So(lifecycle.Hooks(), should.BeEmpty)
+			})
+		})
+	})
+}
@@ -0,0 +1,420 @@
+// Package manifest builds katana injectors from declarative YAML or JSON manifests, so that
+// implementations can be switched without recompiling.
+//
+// Go code registers named constructors and types with a Registry:
+//
+//	registry := manifest.NewRegistry().
+//		Type("Store", (*Store)(nil)).
+//		Constructor("memory", NewMemoryStore).
+//		Constructor("postgres", NewPostgresStore)
+//
+// The manifest then binds types to constructors:
+//
+//	bindings:
+//	  - type: Store
+//	    constructor: postgres
+//	    scope: singleton
+//	    values:
+//	      dsn: postgres://localhost/app
+//
+// Bindings omitting the type are registered under the return type of their constructor. The
+// scope is one of singleton (the default), eager or new. Values are decoded into the first argument
+// of the constructor, which must be a struct value in that case, while the remaining arguments
+// are injected as usual:
+//
+//	func NewPostgresStore(opts PostgresOptions, log *Logger) *PostgresStore
+//
+// JSON manifests are supported as well, JSON being a subset of YAML. Errors point to the line
+// and column of the manifest they originate from.
+package manifest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/drborges/katana"
+	"gopkg.in/yaml.v3"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	ScopeSingleton = "singleton"
+	ScopeEager     = "eager"
+	ScopeNew       = "new"
+)
+
+// Registry holds the constructors and types manifests refer to by name
+type Registry struct {
+	constructors map[string]interface{}
+	types        map[string]interface{}
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		constructors: make(map[string]interface{}),
+		types:        make(map[string]interface{}),
+	}
+}
+
+// Constructor registers the provider function fn under the given name
+func (registry *Registry) Constructor(name string, fn interface{}) *Registry {
+	if err := katana.ValidateProvider(fn); err != nil {
+		panic(err)
+	}
+	registry.constructors[name] = fn
+	return registry
+}
+
+// Type registers the type reference ref under the given name, following the same convention as
+// the katana.Injector#Provide* methods, e.g. (*Store)(nil) for the interface Store
+func (registry *Registry) Type(name string, ref interface{}) *Registry {
+	registry.types[name] = ref
+	return registry
+}
+
+// Build creates a new injector out of the manifest file at path
+func (registry *Registry) Build(path string) (*katana.Injector, error) {
+	injector := katana.New()
+	if err := registry.Load(injector, path); err != nil {
+		return nil, err
+	}
+	return injector, nil
+}
+
+// Load registers the bindings of the manifest file at path with injector
+func (registry *Registry) Load(injector *katana.Injector, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return registry.Apply(injector, path, data)
+}
+
+// Apply registers the bindings of the given manifest with injector. The manifest name is used
+// in error messages, e.g. the path of the manifest file. Every invalid binding is reported.
+func (registry *Registry) Apply(injector *katana.Injector, name string, data []byte) error {
+	var doc struct {
+		Bindings []yaml.Node `yaml:"bindings"`
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&doc); err != nil && err != io.EOF {
+		return ErrManifest{File: name, Err: err}
+	}
+
+	var errs []error
+	for _, node := range doc.Bindings {
+		if err := registry.bind(injector, &node); err != nil {
+			errs = append(errs, ErrManifest{name, err.line, err.column, err.err})
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// binding is a binding declared by a manifest
+type binding struct {
+	Type        yaml.Node `yaml:"type"`
+	Constructor yaml.Node `yaml:"constructor"`
+	Scope       yaml.Node `yaml:"scope"`
+	Values      yaml.Node `yaml:"values"`
+}
+
+var fields = map[string]bool{"type": true, "constructor": true, "scope": true, "values": true}
+
+// bindError is an error found at a particular node of the manifest
+type bindError struct {
+	line, column int
+	err          error
+}
+
+func at(node *yaml.Node, err error) *bindError {
+	return &bindError{node.Line, node.Column, err}
+}
+
+// bind registers the binding declared by the given node with injector
+func (registry *Registry) bind(injector *katana.Injector, node *yaml.Node) (bindErr *bindError) {
+	if node.Kind != yaml.MappingNode {
+		return at(node, ErrInvalidBinding{})
+	}
+
+	// Unlike decoders, nodes do not reject unknown fields
+	for i := 0; i < len(node.Content); i += 2 {
+		if key := node.Content[i]; !fields[key.Value] {
+			return at(key, ErrUnknownField{key.Value})
+		}
+	}
+
+	var b binding
+	if err := node.Decode(&b); err != nil {
+		return at(node, err)
+	}
+
+	if b.Constructor.Value == "" {
+		return at(node, ErrMissingConstructor{})
+	}
+
+	constructor, ok := registry.constructors[b.Constructor.Value]
+	if !ok {
+		return at(&b.Constructor, ErrUnknownConstructor{b.Constructor.Value, names(registry.constructors)})
+	}
+
+	provider, valuesErr := withValues(constructor, &b.Values)
+	if valuesErr != nil {
+		return valuesErr
+	}
+
+	scope := ScopeSingleton
+	if b.Scope.Value != "" {
+		scope = b.Scope.Value
+	}
+
+	if scope != ScopeSingleton && scope != ScopeEager && scope != ScopeNew {
+		return at(&b.Scope, ErrInvalidScope{scope})
+	}
+
+	var ref interface{}
+	if b.Type.Value != "" {
+		if ref, ok = registry.types[b.Type.Value]; !ok {
+			return at(&b.Type, ErrUnknownType{b.Type.Value, names(registry.types)})
+		}
+	}
+
+	// Registration failures such as type mismatches are reported at the binding
+	defer func() {
+		if r := recover(); r != nil {
+			err, ok := r.(error)
+			if !ok {
+				err = fmt.Errorf("%v", r)
+			}
+			bindErr = at(node, err)
+		}
+	}()
+
+	register(injector, scope, ref, provider)
+	return nil
+}
+
+// register registers the provider p with the given scope under the type referenced by ref or,
+// in case ref is nil, under the return type of p
+func register(injector *katana.Injector, scope string, ref interface{}, p katana.Provider) {
+	switch {
+	case ref == nil && scope == ScopeNew:
+		injector.ProvideFunc(p)
+	case ref == nil && scope == ScopeEager:
+		injector.ProvideEagerSingletonFunc(p)
+	case ref == nil:
+		injector.ProvideSingletonFunc(p)
+	case scope == ScopeNew:
+		injector.ProvideNew(ref, p)
+	case scope == ScopeEager:
+		injector.ProvideEagerSingleton(ref, p)
+	default:
+		injector.ProvideSingleton(ref, p)
+	}
+}
+
+// withValues returns a provider calling constructor with the given values decoded into its
+// first argument, or constructor itself in case no values are given
+func withValues(constructor interface{}, values *yaml.Node) (katana.Provider, *bindError) {
+	fn := reflect.ValueOf(constructor)
+	typ := fn.Type()
+
+	if values.Kind == 0 {
+		return constructor, nil
+	}
+
+	if typ.NumIn() == 0 || typ.In(0).Kind() != reflect.Struct {
+		return nil, at(values, ErrUnexpectedValues{typ})
+	}
+
+	// Like binding fields, values are decoded from a node which does not reject unknown fields
+	if err := knownValues(values, typ.In(0)); err != nil {
+		return nil, err
+	}
+
+	opts := reflect.New(typ.In(0))
+	if err := values.Decode(opts.Interface()); err != nil {
+		return nil, at(values, err)
+	}
+
+	in := make([]reflect.Type, typ.NumIn()-1)
+	for i := range in {
+		in[i] = typ.In(i + 1)
+	}
+
+	provider := reflect.MakeFunc(reflect.FuncOf(in, []reflect.Type{typ.Out(0)}, false), func(args []reflect.Value) []reflect.Value {
+		return fn.Call(append([]reflect.Value{opts.Elem()}, args...))
+	})
+
+	return provider.Interface(), nil
+}
+
+// knownValues checks the keys of the given values, and of the values nested under them, against
+// the fields of the struct typ they are decoded into
+func knownValues(values *yaml.Node, typ reflect.Type) *bindError {
+	if values.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	fields, open := valueFields(typ)
+	for i := 0; i+1 < len(values.Content); i += 2 {
+		key, value := values.Content[i], values.Content[i+1]
+
+		field, ok := fields[key.Value]
+		if !ok && open {
+			continue
+		}
+		if !ok {
+			return at(key, ErrUnknownValue{key.Value, typ})
+		}
+
+		for field.Kind() == reflect.Ptr {
+			field = field.Elem()
+		}
+
+		if field.Kind() == reflect.Struct {
+			if err := knownValues(value, field); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// valueFields returns the types of the fields of the struct typ by their YAML names, following
+// the conventions of the YAML decoder. Structs inlining a map accept any other key, thus open.
+func valueFields(typ reflect.Type) (fields map[string]reflect.Type, open bool) {
+	fields = make(map[string]reflect.Type)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+
+		tag := strings.Split(field.Tag.Get("yaml"), ",")
+		if tag[0] == "-" {
+			continue
+		}
+
+		inline := false
+		for _, flag := range tag[1:] {
+			inline = inline || flag == "inline"
+		}
+
+		switch {
+		case inline && field.Type.Kind() == reflect.Map:
+			open = true
+		case inline && field.Type.Kind() == reflect.Struct:
+			inlined, inlinedOpen := valueFields(field.Type)
+			for name, typ := range inlined {
+				fields[name] = typ
+			}
+			open = open || inlinedOpen
+		case tag[0] != "":
+			fields[tag[0]] = field.Type
+		default:
+			fields[strings.ToLower(field.Name)] = field.Type
+		}
+	}
+
+	return fields, open
+}
+
+func names(m map[string]interface{}) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ErrManifest is an error found in a manifest, pointing to its line and column when known
+type ErrManifest struct {
+	File   string
+	Line   int
+	Column int
+	Err    error
+}
+
+func (err ErrManifest) Error() string {
+	if err.Line == 0 {
+		return fmt.Sprintf("%v: %v", err.File, err.Err)
+	}
+	return fmt.Sprintf("%v:%v:%v: %v", err.File, err.Line, err.Column, err.Err)
+}
+
+func (err ErrManifest) Unwrap() error {
+	return err.Err
+}
+
+type ErrInvalidBinding struct{}
+
+func (err ErrInvalidBinding) Error() string {
+	return "Binding must be a mapping of type, constructor, scope and values"
+}
+
+type ErrUnknownField struct {
+	Name string
+}
+
+func (err ErrUnknownField) Error() string {
+	return fmt.Sprintf("Unknown binding field %q", err.Name)
+}
+
+type ErrMissingConstructor struct{}
+
+func (err ErrMissingConstructor) Error() string {
+	return "Binding has no constructor"
+}
+
+type ErrUnknownConstructor struct {
+	Name  string
+	Known []string
+}
+
+func (err ErrUnknownConstructor) Error() string {
+	return fmt.Sprintf("Unknown constructor %q, registered constructors: %v", err.Name, err.Known)
+}
+
+type ErrUnknownType struct {
+	Name  string
+	Known []string
+}
+
+func (err ErrUnknownType) Error() string {
+	return fmt.Sprintf("Unknown type %q, registered types: %v", err.Name, err.Known)
+}
+
+type ErrInvalidScope struct {
+	Scope string
+}
+
+func (err ErrInvalidScope) Error() string {
+	return fmt.Sprintf("Invalid scope %q, expected one of %v, %v or %v", err.Scope, ScopeSingleton, ScopeEager, ScopeNew)
+}
+
+type ErrUnexpectedValues struct {
+	Constructor reflect.Type
+}
+
+func (err ErrUnexpectedValues) Error() string {
+	return fmt.Sprintf("Constructor %v takes no values, its first argument must be a struct", err.Constructor)
+}
+
+type ErrUnknownValue struct {
+	Name    string
+	Options reflect.Type
+}
+
+func (err ErrUnknownValue) Error() string {
+	return fmt.Sprintf("Unknown value %q, %v has no such field", err.Name, err.Options)
+}
@@ -0,0 +1,200 @@
+package manifest_test
+
+import (
+	"context"
+	"errors"
+	"github.com/drborges/katana"
+	"github.com/drborges/katana/manifest"
+	"github.com/smartystreets/assertions/should"
+	. "github.com/smartystreets/goconvey/convey"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+type Store interface {
+	Name() string
+}
+
+type MemoryStore struct{}
+
+func (store *MemoryStore) Name() string { return "memory" }
+
+type PostgresOptions struct {
+	DSN  string `yaml:"dsn"`
+	Pool int    `yaml:"pool"`
+}
+
+type PostgresStore struct {
+	Options PostgresOptions
+	Logger  *Logger
+}
+
+func (store *PostgresStore) Name() string { return "postgres" }
+
+type Logger struct{}
+
+type Service struct {
+	Store Store
+}
+
+func registry() *manifest.Registry {
+	return manifest.NewRegistry().
+		Type("Store", (*Store)(nil)).
+		Constructor("logger", func() *Logger { return &Logger{} }).
+		Constructor("memory", func() *MemoryStore { return &MemoryStore{} }).
+		Constructor("store", func() Store { return &MemoryStore{} }).
+		Constructor("postgres", func(opts PostgresOptions, logger *Logger) *PostgresStore {
+			return &PostgresStore{opts, logger}
+		}).
+		Constructor("service", func(store Store) *Service { return &Service{store} })
+}
+
+func TestRegistryApply(t *testing.T) {
+	Convey("Given I have a registry of constructors", t, func() {
+		registry := registry()
+		injector := katana.New()
+
+		Convey("When I apply a YAML manifest", func() {
+			err := registry.Apply(injector, "app.yaml", []byte(`
+bindings:
+  - constructor: logger
+  - type: Store
+    constructor: postgres
+    values:
+      dsn: postgres://localhost/app
+      pool: 5
+  - constructor: service
+    scope: new
+`))
+
+			Convey("Then the injector is wired as declared", func() {
+				var service1, service2 *Service
+				injector.Resolve(&service1, &service2)
+
+				So(err, should.BeNil)
+				So(service1, should.NotPointTo, service2)
+				So(service1.Store, should.Equal, service2.Store)
+				So(service1.Store.Name(), should.Equal, "postgres")
+				So(service1.Store.(*PostgresStore).Options, should.Resemble, PostgresOptions{"postgres://localhost/app", 5})
+				So(service1.Store.(*PostgresStore).Logger, should.NotBeNil)
+			})
+		})
+
+		Convey("When I apply a manifest binding an interface-returning constructor without a type", func() {
+			err := registry.Apply(injector, "app.yaml", []byte(`
+bindings:
+  - constructor: store
+    scope: eager
+  - constructor: service
+`))
+
+			Convey("Then the binding is registered under the interface type", func() {
+				var service *Service
+
+				So(err, should.BeNil)
+				So(injector.Start(context.Background()), should.BeNil)
+				So(injector.Instantiated(), should.Contain, reflect.TypeOf((*Store)(nil)).Elem())
+
+				injector.Resolve(&service)
+				So(service.Store.Name(), should.Equal, "memory")
+			})
+		})
+
+		Convey("When I apply a JSON manifest", func() {
+			err := registry.Apply(injector, "app.json", []byte(`{
+  "bindings": [
+    {"type": "Store", "constructor": "memory"}
+  ]
+}`))
+
+			Convey("Then the injector is wired as declared", func() {
+				var store Store
+				injector.Resolve(&store)
+
+				So(err, should.BeNil)
+				So(store.Name(), should.Equal, "memory")
+			})
+		})
+
+		Convey("When I apply a manifest with invalid bindings", func() {
+			err := registry.Apply(injector, "app.yaml", []byte(`
+bindings:
+  - type: Store
+    constructor: mysql
+  - type: Cache
+    constructor: memory
+  - constructor: memory
+    scope: request
+  - constructor: logger
+    values:
+      level: debug
+  - type: Store
+    constructor: logger
+  - constructor: logger
+    lifetime: short
+  - constructor: postgres
+    values:
+      dsn: postgres://localhost/app
+      pools: 5
+`))
+
+			Convey("Then every error points to its line in the manifest", func() {
+				var unknownConstructor manifest.ErrUnknownConstructor
+
+				So(err, should.NotBeNil)
+				So(errors.As(err, &unknownConstructor), should.BeTrue)
+				So(unknownConstructor.Known, should.Resemble, []string{"logger", "memory", "postgres", "service", "store"})
+
+				errs := err.(interface{ Unwrap() []error }).Unwrap()
+				So(len(errs), should.Equal, 7)
+				So(errs[0].Error(), should.StartWith, `app.yaml:4:18: Unknown constructor "mysql"`)
+				So(errs[1].Error(), should.StartWith, `app.yaml:5:11: Unknown type "Cache"`)
+				So(errs[2].Error(), should.StartWith, `app.yaml:8:12: Invalid scope "request"`)
+				So(errs[3].Error(), should.StartWith, `app.yaml:11:7: Constructor func() *manifest_test.Logger takes no values`)
+				So(errs[4].Error(), should.StartWith, `app.yaml:12:5: Provided type *manifest_test.Logger is not assignable`)
+				So(errs[5].Error(), should.Equal, `app.yaml:15:5: Unknown binding field "lifetime"`)
+				So(errs[6].Error(), should.Equal, `app.yaml:19:7: Unknown value "pools", manifest_test.PostgresOptions has no such field`)
+			})
+		})
+
+		Convey("When I apply a malformed manifest", func() {
+			err := registry.Apply(injector, "app.yaml", []byte("bindings: [\n"))
+
+			Convey("Then the parse error is reported", func() {
+				So(err, should.HaveSameTypeAs, manifest.ErrManifest{})
+				So(err.Error(), should.StartWith, "app.yaml: yaml:")
+			})
+		})
+	})
+}
+
+func TestRegistryBuild(t *testing.T) {
+	Convey("Given I have a manifest file", t, func() {
+		path := filepath.Join(t.TempDir(), "app.yaml")
+		os.WriteFile(path, []byte("bindings:\n  - type: Store\n    constructor: memory\n"), 0600)
+
+		Convey("When I build an injector out of it", func() {
+			injector, err := registry().Build(path)
+
+			Convey("Then the injector is wired as declared", func() {
+				var store Store
+				injector.Resolve(&store)
+
+				So(err, should.BeNil)
+				So(store.Name(), should.Equal, "memory")
+			})
+		})
+	})
+
+	Convey("Given I have no manifest file", t, func() {
+		Convey("When I build an injector", func() {
+			_, err := registry().Build(filepath.Join(t.TempDir(), "missing.yaml"))
+
+			Convey("Then the read error is returned", func() {
+				So(errors.Is(err, os.ErrNotExist), should.BeTrue)
+			})
+		})
+	})
+}
@@ -0,0 +1,145 @@
+package katana
+
+import (
+	"log/slog"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Observer is notified about the resolution of dependencies, which is useful for logging,
+// collecting metrics and tracing.
+type Observer interface {
+	// OnResolveStart is called whenever an instance of typ is requested. The path holds the
+	// types under resolution that led to the request, from the outermost to the innermost.
+	OnResolveStart(typ reflect.Type, path []string)
+	// OnProviderCalled is called after the provider of typ returned or panicked with err
+	OnProviderCalled(typ reflect.Type, duration time.Duration, err error)
+	// OnCacheHit is called whenever a request for typ is resolved with a cached singleton
+	OnCacheHit(typ reflect.Type)
+}
+
+// Observe registers observers to be notified about the resolution of dependencies by the
+// injector. Observers are inherited by clones of the injector.
+func (injector *Injector) Observe(observers ...Observer) *Injector {
+	injector.observers = append(injector.observers, observers...)
+	return injector
+}
+
+func (injector *Injector) notifyResolveStart(typ reflect.Type, path []string) {
+	path = append([]string(nil), path...)
+	for _, observer := range injector.observers {
+		observer.OnResolveStart(typ, path)
+	}
+}
+
+// call calls the given provider of typ with the given arguments, notifying observers about
+// how long it took and recording the call in case the injector is profiled. The path holds
+// the types under resolution down to typ itself.
+func (injector *Injector) call(typ reflect.Type, path []string, provider reflect.Value, args []reflect.Value) reflect.Value {
+	if len(injector.observers) == 0 && injector.profiler == nil {
+		return provider.Call(args)[0]
+	}
+
+	start := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			injector.called(typ, path, time.Since(start), asError(r))
+			panic(r)
+		}
+	}()
+
+	inst := provider.Call(args)[0]
+	injector.called(typ, path, time.Since(start), nil)
+
+	return inst
+}
+
+func (injector *Injector) called(typ reflect.Type, path []string, duration time.Duration, err error) {
+	if injector.profiler != nil {
+		injector.profiler.record(typ, path, duration)
+	}
+	for _, observer := range injector.observers {
+		observer.OnProviderCalled(typ, duration, err)
+	}
+}
+
+// SlogObserver is an Observer logging resolution events with a slog.Logger at debug level,
+// or at error level in case of provider failures.
+type SlogObserver struct {
+	Logger *slog.Logger
+}
+
+// NewSlogObserver returns an Observer logging with the given logger, or slog.Default()
+// in case the logger is nil.
+func NewSlogObserver(logger *slog.Logger) *SlogObserver {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &SlogObserver{logger}
+}
+
+func (observer *SlogObserver) OnResolveStart(typ reflect.Type, path []string) {
+	observer.Logger.Debug("katana: resolving", "type", typ.String(), "path", path)
+}
+
+func (observer *SlogObserver) OnProviderCalled(typ reflect.Type, duration time.Duration, err error) {
+	if err != nil {
+		observer.Logger.Error("katana: provider failed", "type", typ.String(), "duration", duration, "error", err)
+		return
+	}
+	observer.Logger.Debug("katana: provider called", "type", typ.String(), "duration", duration)
+}
+
+func (observer *SlogObserver) OnCacheHit(typ reflect.Type) {
+	observer.Logger.Debug("katana: cache hit", "type", typ.String())
+}
+
+// EventKind describes the kind of a recorded Event
+type EventKind string
+
+const (
+	EventResolveStart   = EventKind("ResolveStart")
+	EventProviderCalled = EventKind("ProviderCalled")
+	EventCacheHit       = EventKind("CacheHit")
+)
+
+// Event is a resolution event recorded by an EventRecorder
+type Event struct {
+	Kind     EventKind
+	Type     reflect.Type
+	Path     []string
+	Duration time.Duration
+	Err      error
+}
+
+// EventRecorder is an Observer keeping every resolution event in memory, mostly useful in tests
+type EventRecorder struct {
+	mutex  sync.Mutex
+	events []Event
+}
+
+func (recorder *EventRecorder) record(event Event) {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	recorder.events = append(recorder.events, event)
+}
+
+// Events returns a copy of the recorded events in the order they happened
+func (recorder *EventRecorder) Events() []Event {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	return append([]Event(nil), recorder.events...)
+}
+
+func (recorder *EventRecorder) OnResolveStart(typ reflect.Type, path []string) {
+	recorder.record(Event{Kind: EventResolveStart, Type: typ, Path: path})
+}
+
+func (recorder *EventRecorder) OnProviderCalled(typ reflect.Type, duration time.Duration, err error) {
+	recorder.record(Event{Kind: EventProviderCalled, Type: typ, Duration: duration, Err: err})
+}
+
+func (recorder *EventRecorder) OnCacheHit(typ reflect.Type) {
+	recorder.record(Event{Kind: EventCacheHit, Type: typ})
+}
@@ -0,0 +1,107 @@
+package katana_test
+
+import (
+	"bytes"
+	"errors"
+	"github.com/drborges/katana"
+	"github.com/smartystreets/assertions/should"
+	. "github.com/smartystreets/goconvey/convey"
+	"log/slog"
+	"reflect"
+	"testing"
+)
+
+func TestInjectorObserve(t *testing.T) {
+	Convey("Given I have an injector observed by an event recorder", t, func() {
+		recorder := &katana.EventRecorder{}
+		injector := katana.New().
+			Observe(recorder).
+			ProvideSingleton(&Dependency{}, func() *Dependency {
+				return &Dependency{}
+			}).
+			ProvideNew(&DependencyA{}, func(dep *Dependency) *DependencyA {
+				return &DependencyA{dep}
+			})
+
+		Convey("When I resolve a dependency twice", func() {
+			var depA1, depA2 *DependencyA
+			injector.Resolve(&depA1)
+			injector.Resolve(&depA2)
+
+			Convey("Then the resolution events are recorded in order", func() {
+				depType := reflect.TypeOf(&Dependency{})
+				depAType := reflect.TypeOf(&DependencyA{})
+				events := recorder.Events()
+
+				So(len(events), should.Equal, 8)
+				So(events[0].Kind, should.Equal, katana.EventResolveStart)
+				So(events[0].Type, should.Equal, depAType)
+				So(events[0].Path, should.BeEmpty)
+				So(events[1].Kind, should.Equal, katana.EventResolveStart)
+				So(events[1].Type, should.Equal, depType)
+				So(events[1].Path, should.Resemble, []string{depAType.String()})
+				So(events[2].Kind, should.Equal, katana.EventProviderCalled)
+				So(events[2].Type, should.Equal, depType)
+				So(events[3].Kind, should.Equal, katana.EventProviderCalled)
+				So(events[3].Type, should.Equal, depAType)
+				So(events[3].Err, should.BeNil)
+				So(events[4].Kind, should.Equal, katana.EventResolveStart)
+				So(events[5].Kind, should.Equal, katana.EventResolveStart)
+				So(events[6].Kind, should.Equal, katana.EventCacheHit)
+				So(events[6].Type, should.Equal, depType)
+				So(events[7].Kind, should.Equal, katana.EventProviderCalled)
+				So(events[7].Type, should.Equal, depAType)
+			})
+		})
+
+		Convey("When a provider panics", func() {
+			injector.ProvideNew(&DependencyB{}, func(_ *DependencyA) *DependencyB {
+				panic(errors.New("boom"))
+			})
+
+			var depB *DependencyB
+			r := recovered(func() { injector.Resolve(&depB) })
+
+			Convey("Then the panic is propagated and reported to observers", func() {
+				events := recorder.Events()
+				last := events[len(events)-1]
+
+				So(r, should.Resemble, errors.New("boom"))
+				So(last.Kind, should.Equal, katana.EventProviderCalled)
+				So(last.Type, should.Equal, reflect.TypeOf(&DependencyB{}))
+				So(last.Err, should.Resemble, errors.New("boom"))
+			})
+		})
+
+		Convey("When I clone the injector", func() {
+			var dep *Dependency
+			injector.Clone().Resolve(&dep)
+
+			Convey("Then its observers are inherited", func() {
+				So(recorder.Events(), should.NotBeEmpty)
+			})
+		})
+	})
+
+	Convey("Given I have an injector observed by a slog observer", t, func() {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		injector := katana.New().
+			Observe(katana.NewSlogObserver(logger)).
+			ProvideSingleton(&Dependency{}, func() *Dependency {
+				return &Dependency{}
+			})
+
+		Convey("When I resolve a dependency", func() {
+			var dep *Dependency
+			injector.Resolve(&dep)
+			injector.Resolve(&dep)
+
+			Convey("Then the resolution is logged", func() {
+				So(buf.String(), should.ContainSubstring, `msg="katana: resolving" type=*katana_test.Dependency`)
+				So(buf.String(), should.ContainSubstring, `msg="katana: provider called" type=*katana_test.Dependency`)
+				So(buf.String(), should.ContainSubstring, `msg="katana: cache hit" type=*katana_test.Dependency`)
+			})
+		})
+	})
+}
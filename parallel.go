@@ -0,0 +1,276 @@
+package katana
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Parallel enables parallel resolution: the independent dependencies of a resolution, such as
+// the arguments of a function passed to Injector#Inject, are resolved concurrently, calling at
+// most the given number of providers at a time. A limit of zero disables parallel resolution.
+//
+// Singletons are still instantiated exactly once. The first provider failure cancels the
+// resolution: providers not yet called are skipped, providers taking a context.Context receive
+// a context cancelled upon failure, and the failure is reported once every running provider
+// returned.
+//
+// Missing providers and cyclic dependencies are detected before any provider is called.
+// Observers must be safe for concurrent use while resolving in parallel.
+//
+// Providers resolving further dependencies through the injector itself, e.g. by taking a
+// *Injector argument, join the ongoing parallel resolution: their dependencies are resolved
+// concurrently with a worker limit of their own, so nested resolutions cannot starve waiting
+// for the workers held by their callers. Cyclic dependencies introduced by nested resolutions
+// fail with ErrCyclicDependency, provided the nested resolution happens on the goroutine the
+// provider was called on.
+func (injector *Injector) Parallel(workers int) *Injector {
+	injector.workers = workers
+	return injector
+}
+
+// future is a singleton under construction by a parallel resolution
+type future struct {
+	done  chan struct{}
+	value reflect.Value
+	err   error
+}
+
+// parallel is an ongoing parallel resolution
+type parallel struct {
+	injector *Injector
+	ctx      context.Context
+	cancel   context.CancelFunc
+	workers  chan struct{}
+	once     sync.Once
+	err      error
+	// callers maps the goroutines calling providers to the resolution path of the provider, so
+	// that nested resolutions started by the provider continue that path
+	callers *sync.Map
+}
+
+// resolveParallel returns an instance for each of the given slots, resolving independent
+// dependencies concurrently
+func (injector *Injector) resolveParallel(slots []slot) []reflect.Value {
+	// Plans the whole resolution upfront, so that missing providers and cycles are detected
+	// before any provider is called and plans are only read while resolving concurrently
+	checked := make(map[reflect.Type]bool)
+	for _, s := range slots {
+		injector.prepare(s, checked)
+	}
+
+	ctx, cancel := context.WithCancel(injector.context())
+	defer cancel()
+	defer injector.bind(ctx)()
+
+	p := &parallel{
+		injector: injector,
+		ctx:      ctx,
+		cancel:   cancel,
+		workers:  make(chan struct{}, injector.workers),
+		callers:  &sync.Map{},
+	}
+
+	// Nested resolutions started by providers while workers are running join this one rather
+	// than touching the trace, plans and context of the injector concurrently
+	injector.running = p
+	defer func() { injector.running = nil }()
+
+	insts, _ := p.all(slots, nil)
+	if p.err != nil {
+		panic(p.err)
+	}
+
+	return insts
+}
+
+// nested resolves the given slots on behalf of a provider called by the resolution p,
+// panicking with the first failure as sequential resolutions do. Nested resolutions are
+// cancelled along with p.
+func (p *parallel) nested(slots []slot) []reflect.Value {
+	ctx, cancel := context.WithCancel(p.ctx)
+	defer cancel()
+
+	n := &parallel{
+		injector: p.injector,
+		ctx:      ctx,
+		cancel:   cancel,
+		workers:  make(chan struct{}, cap(p.workers)),
+		callers:  p.callers,
+	}
+
+	var path []string
+	if caller, ok := p.callers.Load(goid()); ok {
+		path = caller.([]string)
+	}
+
+	insts, _ := n.all(slots, path)
+	if n.err != nil {
+		panic(n.err)
+	}
+
+	return insts
+}
+
+// prepare plans the resolution of the given slot and its dependencies recursively, panicking
+// on missing providers and cyclic dependencies as sequential resolutions do
+func (injector *Injector) prepare(s slot, checked map[reflect.Type]bool) {
+	if checked[s.key] {
+		return
+	}
+
+	if _, cached := injector.instances[s.key]; cached {
+		return
+	}
+
+	if err := injector.trace.Push(s.name); err != nil {
+		panic(err)
+	}
+
+	for _, dep := range injector.plan(reflect.TypeOf(s.injectable.Provider)) {
+		injector.prepare(dep, checked)
+	}
+
+	injector.trace.Pop()
+	checked[s.key] = true
+}
+
+// all resolves the given slots concurrently
+func (p *parallel) all(slots []slot, path []string) ([]reflect.Value, error) {
+	insts := make([]reflect.Value, len(slots))
+	errs := make([]error, len(slots))
+
+	if len(slots) == 1 {
+		insts[0], errs[0] = p.instance(slots[0], path)
+		return insts, errs[0]
+	}
+
+	var wg sync.WaitGroup
+	for i, s := range slots {
+		wg.Add(1)
+		go func(i int, s slot) {
+			defer wg.Done()
+			insts[i], errs[i] = p.instance(s, path)
+		}(i, s)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return insts, err
+		}
+	}
+
+	return insts, nil
+}
+
+// instance works like Injector#instance, except dependencies are resolved concurrently and
+// singletons under construction are awaited rather than constructed again
+func (p *parallel) instance(s slot, path []string) (inst reflect.Value, err error) {
+	injector := p.injector
+
+	// Cycles are detected upfront, except for the ones introduced by nested resolutions which
+	// would otherwise wait on their own singletons forever
+	for _, name := range path {
+		if name == s.name {
+			return inst, p.fail(ErrCyclicDependency{&Trace{Types: append(path[:len(path):len(path)], s.name)}})
+		}
+	}
+
+	if len(injector.observers) > 0 {
+		injector.notifyResolveStart(s.key, path)
+	}
+
+	if s.injectable.Type == TypeSingleton {
+		injector.mutex.Lock()
+
+		if cached, ok := injector.instances[s.key]; ok {
+			injector.mutex.Unlock()
+			for _, observer := range injector.observers {
+				observer.OnCacheHit(s.key)
+			}
+			return valueOf(cached, s.key), nil
+		}
+
+		if f, ok := injector.pending[s.key]; ok {
+			injector.mutex.Unlock()
+			<-f.done
+			return f.value, f.err
+		}
+
+		f := &future{done: make(chan struct{})}
+		if injector.pending == nil {
+			injector.pending = make(map[reflect.Type]*future)
+		}
+		injector.pending[s.key] = f
+		injector.mutex.Unlock()
+
+		defer func() {
+			injector.mutex.Lock()
+			if err == nil {
+				injector.instances[s.key] = inst.Interface()
+				injector.singletons = append(injector.singletons, s.key)
+			}
+			delete(injector.pending, s.key)
+			injector.mutex.Unlock()
+
+			f.value, f.err = inst, err
+			close(f.done)
+		}()
+	}
+
+	path = append(path[:len(path):len(path)], s.name)
+	provider := reflect.ValueOf(s.injectable.Provider)
+
+	args, err := p.all(injector.plan(provider.Type()), path)
+	if err != nil {
+		return inst, err
+	}
+
+	if err := p.ctx.Err(); err != nil {
+		return inst, p.fail(ErrResolutionAborted{err, &Trace{Types: path}})
+	}
+
+	p.workers <- struct{}{}
+	defer func() { <-p.workers }()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = p.fail(asError(r))
+		}
+	}()
+
+	id := goid()
+	p.callers.Store(id, path)
+	defer p.callers.Delete(id)
+
+	return unwrap(injector.call(s.key, path, provider, args), s.key), nil
+}
+
+// goid returns the id of the current goroutine, as reported in the header of its stack trace
+func goid() uint64 {
+	var buf [64]byte
+	header := strings.TrimPrefix(string(buf[:runtime.Stack(buf[:], false)]), "goroutine ")
+	id, _ := strconv.ParseUint(header[:strings.IndexByte(header, ' ')], 10, 64)
+	return id
+}
+
+// fail records the first failure of the resolution, cancelling it
+func (p *parallel) fail(err error) error {
+	p.once.Do(func() {
+		p.err = err
+		p.cancel()
+	})
+	return err
+}
+
+// resetTrace discards the trace of a failed resolution. Nested resolutions failing during a
+// parallel resolution leave it untouched, since they do not use it.
+func (injector *Injector) resetTrace() {
+	if injector.running == nil {
+		injector.trace = NewTrace()
+	}
+}
@@ -0,0 +1,318 @@
+package katana_test
+
+import (
+	"context"
+	"errors"
+	"github.com/drborges/katana"
+	"github.com/smartystreets/assertions/should"
+	. "github.com/smartystreets/goconvey/convey"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type DB struct{ Pool *Pool }
+type Cache struct{ Pool *Pool }
+type SearchClient struct{ Pool *Pool }
+type Pool struct{}
+type Publisher struct{ Subscriber *Subscriber }
+type Subscriber struct{ Publisher *Publisher }
+
+// gauge tracks how many providers are running at once
+type gauge struct {
+	running, max int32
+}
+
+func (g *gauge) enter() {
+	n := atomic.AddInt32(&g.running, 1)
+	for {
+		max := atomic.LoadInt32(&g.max)
+		if n <= max || atomic.CompareAndSwapInt32(&g.max, max, n) {
+			return
+		}
+	}
+}
+
+func (g *gauge) leave() {
+	atomic.AddInt32(&g.running, -1)
+}
+
+func parallelInjector(g *gauge, barrier *sync.WaitGroup, pools *int32, eager bool) *katana.Injector {
+	// connect simulates a slow connection, waiting for the other connections to be underway
+	connect := func() {
+		g.enter()
+		defer g.leave()
+
+		if barrier == nil {
+			time.Sleep(10 * time.Millisecond)
+			return
+		}
+
+		barrier.Done()
+		done := make(chan struct{})
+		go func() { barrier.Wait(); close(done) }()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+		}
+	}
+
+	injector := katana.New().
+		ProvideSingleton(&Pool{}, func() *Pool {
+			atomic.AddInt32(pools, 1)
+			time.Sleep(10 * time.Millisecond)
+			return &Pool{}
+		})
+
+	provide := injector.ProvideSingleton
+	if eager {
+		provide = injector.ProvideEagerSingleton
+	}
+
+	provide(&DB{}, func(pool *Pool) *DB {
+		connect()
+		return &DB{pool}
+	})
+	provide(&Cache{}, func(pool *Pool) *Cache {
+		connect()
+		return &Cache{pool}
+	})
+	provide(&SearchClient{}, func(pool *Pool) *SearchClient {
+		connect()
+		return &SearchClient{pool}
+	})
+
+	return injector
+}
+
+func TestInjectorParallel(t *testing.T) {
+	Convey("Given I have an injector resolving in parallel", t, func() {
+		g := &gauge{}
+		barrier := &sync.WaitGroup{}
+		barrier.Add(3)
+		var pools int32
+		injector := parallelInjector(g, barrier, &pools, false).Parallel(3)
+
+		Convey("When I inject a function depending on slow providers", func() {
+			var db *DB
+			var cache *Cache
+			var search *SearchClient
+			injector.Inject(func(d *DB, c *Cache, s *SearchClient) {
+				db, cache, search = d, c, s
+			})()
+
+			Convey("Then independent dependencies are constructed concurrently", func() {
+				So(atomic.LoadInt32(&g.max), should.Equal, 3)
+				So(db, should.NotBeNil)
+				So(cache, should.NotBeNil)
+				So(search, should.NotBeNil)
+			})
+
+			Convey("Then shared singletons are constructed exactly once", func() {
+				So(atomic.LoadInt32(&pools), should.Equal, 1)
+				So(db.Pool, should.Equal, cache.Pool)
+				So(db.Pool, should.Equal, search.Pool)
+				So(injector.Instantiated(), should.HaveLength, 4)
+			})
+
+			Convey("Then singletons are cached for further resolutions", func() {
+				var other *DB
+				injector.Resolve(&other)
+
+				So(other, should.Equal, db)
+			})
+		})
+	})
+
+	Convey("Given I have an injector resolving eager singletons in parallel", t, func() {
+		g := &gauge{}
+		barrier := &sync.WaitGroup{}
+		barrier.Add(3)
+		var pools int32
+		injector := parallelInjector(g, barrier, &pools, true).Parallel(3)
+
+		Convey("When I start the injector", func() {
+			err := injector.Start(context.Background())
+
+			Convey("Then independent eager singletons are instantiated concurrently", func() {
+				So(err, should.BeNil)
+				So(atomic.LoadInt32(&g.max), should.Equal, 3)
+				So(injector.Instantiated(), should.HaveLength, 4)
+			})
+		})
+	})
+
+	Convey("Given I have an injector resolving in parallel with a worker limit", t, func() {
+		g := &gauge{}
+		var pools int32
+		injector := parallelInjector(g, nil, &pools, false).Parallel(2)
+
+		Convey("When I resolve several slow dependencies", func() {
+			var db *DB
+			var cache *Cache
+			var search *SearchClient
+			injector.Resolve(&db, &cache, &search)
+
+			Convey("Then at most the given number of providers run at once", func() {
+				So(atomic.LoadInt32(&g.max), should.Equal, 2)
+				So(atomic.LoadInt32(&pools), should.Equal, 1)
+			})
+		})
+	})
+
+	Convey("Given I have an injector resolving in parallel with providers resolving through the injector", t, func() {
+		var pools int32
+		injector := katana.New().
+			Parallel(1).
+			ProvideSingleton(&Pool{}, func() *Pool {
+				atomic.AddInt32(&pools, 1)
+				time.Sleep(10 * time.Millisecond)
+				return &Pool{}
+			}).
+			ProvideNew(&DB{}, func(injector *katana.Injector) *DB {
+				var pool *Pool
+				injector.Resolve(&pool)
+				return &DB{pool}
+			}).
+			ProvideNew(&Cache{}, func(injector *katana.Injector) *Cache {
+				var pool *Pool
+				err := injector.ResolveContext(context.Background(), &pool)
+				if err != nil {
+					panic(err)
+				}
+				return &Cache{pool}
+			}).
+			ProvideNew(&SearchClient{}, func(injector *katana.Injector) *SearchClient {
+				var cfg *FeatureConfig
+				injector.Resolve(&cfg)
+				return &SearchClient{}
+			})
+
+		Convey("When I inject a function depending on them", func() {
+			var db *DB
+			var cache *Cache
+			injector.Inject(func(d *DB, c *Cache) {
+				db, cache = d, c
+			})()
+
+			Convey("Then nested resolutions join the parallel resolution without racing", func() {
+				So(atomic.LoadInt32(&pools), should.Equal, 1)
+				So(db.Pool, should.Equal, cache.Pool)
+			})
+
+			Convey("Then plans are cached again once the parallel resolution is done", func() {
+				var other *DB
+				injector.Resolve(&other)
+
+				So(other.Pool, should.Equal, db.Pool)
+			})
+		})
+
+		Convey("When a nested resolution fails", func() {
+			var search *SearchClient
+			var db *DB
+			err := injector.ResolveContext(context.Background(), &search, &db)
+
+			Convey("Then the failure is reported by the parallel resolution", func() {
+				So(err, should.Resemble, katana.ErrNoSuchProvider{reflect.TypeOf(&FeatureConfig{})})
+			})
+		})
+	})
+
+	Convey("Given I have singletons depending on each other through a nested resolution", t, func() {
+		cyclic := func() *katana.Injector {
+			return katana.New().
+				ProvideSingleton(&Publisher{}, func(injector *katana.Injector) *Publisher {
+					var subscriber *Subscriber
+					injector.Resolve(&subscriber)
+					return &Publisher{subscriber}
+				}).
+				ProvideSingleton(&Subscriber{}, func(publisher *Publisher) *Subscriber {
+					return &Subscriber{publisher}
+				})
+		}
+
+		Convey("Then resolving them in parallel fails as resolving them sequentially does", func() {
+			var publisher *Publisher
+			expected := recovered(func() { cyclic().Resolve(&publisher) })
+
+			done := make(chan interface{})
+			go func() {
+				done <- recovered(func() { cyclic().Parallel(2).Resolve(&publisher) })
+			}()
+
+			select {
+			case r := <-done:
+				So(expected, should.HaveSameTypeAs, katana.ErrCyclicDependency{})
+				So(r, should.Resemble, expected)
+			case <-time.After(2 * time.Second):
+				So("parallel resolution", should.Equal, "not deadlocked")
+			}
+		})
+	})
+
+	Convey("Given I have an injector resolving in parallel with a failing provider", t, func() {
+		var called, cancelled int32
+		injector := katana.New().
+			Parallel(4).
+			ProvideNew(&DB{}, func() *DB {
+				time.Sleep(20 * time.Millisecond)
+				panic(errors.New("connection refused"))
+			}).
+			ProvideNew(&Cache{}, func(ctx context.Context) *Cache {
+				select {
+				case <-ctx.Done():
+					atomic.AddInt32(&cancelled, 1)
+				case <-time.After(time.Second):
+				}
+				return &Cache{}
+			}).
+			ProvideNew(&Pool{}, func() *Pool {
+				time.Sleep(50 * time.Millisecond)
+				return &Pool{}
+			}).
+			ProvideNew(&SearchClient{}, func(pool *Pool) *SearchClient {
+				atomic.AddInt32(&called, 1)
+				return &SearchClient{pool}
+			})
+
+		Convey("When I resolve its dependencies", func() {
+			var db *DB
+			var cache *Cache
+			var search *SearchClient
+			err := injector.ResolveContext(context.Background(), &db, &cache, &search)
+
+			Convey("Then the first error is reported and the rest of the resolution cancelled", func() {
+				So(err, should.Resemble, errors.New("connection refused"))
+				So(atomic.LoadInt32(&cancelled), should.Equal, 1)
+				So(atomic.LoadInt32(&called), should.Equal, 0)
+			})
+		})
+	})
+
+	Convey("Given I have an injector resolving in parallel with a missing provider", t, func() {
+		var called int32
+		injector := katana.New().
+			Parallel(4).
+			ProvideNew(&Cache{}, func() *Cache {
+				atomic.AddInt32(&called, 1)
+				return &Cache{}
+			}).
+			ProvideNew(&DB{}, func(pool *Pool) *DB {
+				return &DB{pool}
+			})
+
+		Convey("When I resolve its dependencies", func() {
+			var cache *Cache
+			var db *DB
+			err := injector.ResolveContext(context.Background(), &cache, &db)
+
+			Convey("Then the failure is reported before any provider is called", func() {
+				So(err, should.Resemble, katana.ErrNoSuchProvider{reflect.TypeOf(&Pool{})})
+				So(atomic.LoadInt32(&called), should.Equal, 0)
+			})
+		})
+	})
+}
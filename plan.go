@@ -0,0 +1,66 @@
+package katana
+
+import (
+	"reflect"
+)
+
+// slot is a dependency planned for resolution: the type under which the injectable
+// resolving the dependency is registered along with the injectable itself.
+type slot struct {
+	key        reflect.Type
+	name       string
+	injectable *Injectable
+}
+
+// slot returns the slot planned for resolving the given type.
+//
+// Slots are cached so that looking up a type, possibly scanning the registered injectables
+// for implementations of an interface, happens once until the registrations change. Caches
+// are left untouched during parallel resolutions, which read them concurrently.
+func (injector *Injector) slot(typ reflect.Type) slot {
+	if s, planned := injector.slots[typ]; planned {
+		return s
+	}
+
+	var s slot
+	s.key, s.injectable = injector.lookup(typ)
+	s.name = s.key.String()
+
+	if injector.caching() {
+		injector.slots[typ] = s
+	}
+
+	return s
+}
+
+// plan returns the slots planned for resolving the arguments of the given function type,
+// in the order they are passed to the function.
+//
+// Plans are cached per function type and invalidated whenever a provider is registered.
+func (injector *Injector) plan(fn reflect.Type) []slot {
+	if slots, planned := injector.plans[fn]; planned {
+		return slots
+	}
+
+	slots := make([]slot, fn.NumIn())
+	for i := range slots {
+		slots[i] = injector.slot(fn.In(i))
+	}
+
+	if injector.caching() {
+		injector.plans[fn] = slots
+	}
+
+	return slots
+}
+
+// caching returns true in case slots and plans may be cached
+func (injector *Injector) caching() bool {
+	return !injector.unplanned && injector.running == nil
+}
+
+// invalidatePlans discards all cached slots and plans
+func (injector *Injector) invalidatePlans() {
+	injector.slots = make(map[reflect.Type]slot)
+	injector.plans = make(map[reflect.Type][]slot)
+}
@@ -0,0 +1,91 @@
+package katana_test
+
+import (
+	"github.com/drborges/katana"
+	"github.com/smartystreets/assertions/should"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestInjectorPlans(t *testing.T) {
+	Convey("Given I have resolved an interface through interface scanning", t, func() {
+		injector := katana.New().ScanInterfaces().Provide(&InterfaceDependencyImpl{})
+
+		var dep InterfaceDependency
+		injector.Resolve(&dep)
+
+		Convey("When I register another implementation of the interface", func() {
+			injector.Provide(&OtherInterfaceDependencyImpl{})
+
+			Convey("Then the cached resolution plan is invalidated", func() {
+				So(func() { injector.Resolve(&dep) }, should.Panic)
+			})
+		})
+	})
+
+	Convey("Given I have failed to resolve a function with a missing dependency", t, func() {
+		injector := katana.New()
+		fn := func(dep *Dependency) string { return dep.Field }
+
+		So(func() { injector.Inject(fn) }, should.Panic)
+
+		Convey("When I register the missing dependency", func() {
+			injector.Provide(&Dependency{Field: "registered"})
+
+			Convey("Then the function arguments are resolved", func() {
+				So(injector.Inject(fn)().First(), should.Equal, "registered")
+			})
+		})
+	})
+}
+
+func newBenchmarkInjector() *katana.Injector {
+	return katana.New().
+		Provide(&Dependency{}).
+		ProvideNew(&DependencyA{}, func(dep *Dependency) *DependencyA {
+			return &DependencyA{dep}
+		}).
+		ProvideNew(&DependencyB{}, func(dep *DependencyA) *DependencyB {
+			return &DependencyB{dep}
+		})
+}
+
+func BenchmarkResolvePlanned(b *testing.B) {
+	injector := newBenchmarkInjector()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var dep *DependencyB
+		injector.Resolve(&dep)
+	}
+}
+
+func BenchmarkResolveUnplanned(b *testing.B) {
+	injector := newBenchmarkInjector().DisablePlans()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var dep *DependencyB
+		injector.Resolve(&dep)
+	}
+}
+
+func BenchmarkInjectPlanned(b *testing.B) {
+	injector := newBenchmarkInjector()
+	fn := func(b *DependencyB, a *DependencyA, dep *Dependency) {}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		injector.Inject(fn)()
+	}
+}
+
+func BenchmarkInjectUnplanned(b *testing.B) {
+	injector := newBenchmarkInjector().DisablePlans()
+	fn := func(b *DependencyB, a *DependencyA, dep *Dependency) {}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		injector.Inject(fn)()
+	}
+}
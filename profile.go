@@ -0,0 +1,123 @@
+package katana
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ProviderStats holds the calls made to the provider of a type while profiling, see Injector#Profile
+type ProviderStats struct {
+	Type  reflect.Type
+	Calls int
+	Total time.Duration
+	Max   time.Duration
+}
+
+// Profile enables profiling of the injector: from now on the calls to every provider are
+// accumulated, including the ones made by clones of the injector, and can be inspected
+// with Injector#Stats and Injector#WriteFoldedStacks.
+//
+// Provider durations do not include the resolution of their arguments.
+func (injector *Injector) Profile() *Injector {
+	if injector.profiler == nil {
+		injector.profiler = &profiler{
+			stats:  make(map[reflect.Type]*ProviderStats),
+			folded: make(map[string]time.Duration),
+		}
+	}
+	return injector
+}
+
+// Stats returns the accumulated provider stats, slowest providers first. Returns nil unless
+// profiling is enabled.
+func (injector *Injector) Stats() []ProviderStats {
+	if injector.profiler == nil {
+		return nil
+	}
+	return injector.profiler.Stats()
+}
+
+// WriteFoldedStacks writes the accumulated provider durations in nanoseconds keyed by
+// resolution path in the folded stack format, one path per line, which can be rendered
+// by flamegraph tools:
+//
+//	*app.AccountService;*app.Repository;*sql.DB 1203410
+//
+// Writes nothing unless profiling is enabled.
+func (injector *Injector) WriteFoldedStacks(w io.Writer) error {
+	if injector.profiler == nil {
+		return nil
+	}
+	return injector.profiler.WriteFoldedStacks(w)
+}
+
+// profiler accumulates provider stats, safe for use by concurrent clones of the profiled injector
+type profiler struct {
+	mutex  sync.Mutex
+	stats  map[reflect.Type]*ProviderStats
+	folded map[string]time.Duration
+}
+
+// record accumulates a call to the provider of typ, path holding the types under resolution
+// from the outermost down to typ itself
+func (profiler *profiler) record(typ reflect.Type, path []string, duration time.Duration) {
+	profiler.mutex.Lock()
+	defer profiler.mutex.Unlock()
+
+	stats, ok := profiler.stats[typ]
+	if !ok {
+		stats = &ProviderStats{Type: typ}
+		profiler.stats[typ] = stats
+	}
+
+	stats.Calls++
+	stats.Total += duration
+	if duration > stats.Max {
+		stats.Max = duration
+	}
+
+	profiler.folded[strings.Join(path, ";")] += duration
+}
+
+func (profiler *profiler) Stats() []ProviderStats {
+	profiler.mutex.Lock()
+	defer profiler.mutex.Unlock()
+
+	stats := make([]ProviderStats, 0, len(profiler.stats))
+	for _, s := range profiler.stats {
+		stats = append(stats, *s)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Total != stats[j].Total {
+			return stats[i].Total > stats[j].Total
+		}
+		return stats[i].Type.String() < stats[j].Type.String()
+	})
+
+	return stats
+}
+
+func (profiler *profiler) WriteFoldedStacks(w io.Writer) error {
+	profiler.mutex.Lock()
+	defer profiler.mutex.Unlock()
+
+	paths := make([]string, 0, len(profiler.folded))
+	for path := range profiler.folded {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		if _, err := fmt.Fprintf(w, "%v %d\n", path, profiler.folded[path].Nanoseconds()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
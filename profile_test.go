@@ -0,0 +1,70 @@
+package katana_test
+
+import (
+	"bytes"
+	"github.com/drborges/katana"
+	"github.com/smartystreets/assertions/should"
+	. "github.com/smartystreets/goconvey/convey"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInjectorProfile(t *testing.T) {
+	Convey("Given I have an injector with a slow provider", t, func() {
+		injector := katana.New().
+			ProvideNew(&Dependency{}, func() *Dependency {
+				time.Sleep(5 * time.Millisecond)
+				return &Dependency{}
+			}).
+			ProvideNew(&DependencyA{}, func(dep *Dependency) *DependencyA {
+				return &DependencyA{dep}
+			})
+
+		Convey("When it is not profiled", func() {
+			var depA *DependencyA
+			injector.Resolve(&depA)
+
+			Convey("Then no stats are collected", func() {
+				var buf bytes.Buffer
+
+				So(injector.Stats(), should.BeNil)
+				So(injector.WriteFoldedStacks(&buf), should.BeNil)
+				So(buf.Len(), should.Equal, 0)
+			})
+		})
+
+		Convey("When it is profiled", func() {
+			injector.Profile()
+
+			var depA *DependencyA
+			injector.Resolve(&depA)
+			injector.Clone().Resolve(&depA)
+
+			Convey("Then provider stats are accumulated, including the ones of clones", func() {
+				stats := injector.Stats()
+
+				So(len(stats), should.Equal, 2)
+				So(stats[0].Type, should.Equal, reflect.TypeOf(&Dependency{}))
+				So(stats[0].Calls, should.Equal, 2)
+				So(stats[0].Total, should.BeGreaterThanOrEqualTo, 10*time.Millisecond)
+				So(stats[0].Max, should.BeGreaterThanOrEqualTo, 5*time.Millisecond)
+				So(stats[0].Max, should.BeLessThanOrEqualTo, stats[0].Total)
+				So(stats[1].Type, should.Equal, reflect.TypeOf(&DependencyA{}))
+				So(stats[1].Calls, should.Equal, 2)
+				So(stats[1].Total, should.BeLessThan, stats[0].Total)
+			})
+
+			Convey("Then folded stacks are keyed by resolution path", func() {
+				var buf bytes.Buffer
+				So(injector.WriteFoldedStacks(&buf), should.BeNil)
+
+				lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+				So(len(lines), should.Equal, 2)
+				So(lines[0], should.StartWith, "*katana_test.DependencyA ")
+				So(lines[1], should.StartWith, "*katana_test.DependencyA;*katana_test.Dependency ")
+			})
+		})
+	})
+}
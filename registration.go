@@ -0,0 +1,139 @@
+package katana
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// modulePath is the import path of this package, which katana's other packages are nested under
+var modulePath = reflect.TypeOf(Injector{}).PkgPath()
+
+// Registration describes a type registered with the injector
+type Registration struct {
+	Type           reflect.Type
+	InjectableType InjectableType
+	// Provider is the signature of the registered provider function
+	Provider reflect.Type
+	// Location is the file and line where the type was registered
+	Location string
+	// Instantiated is true for singletons already instantiated and cached by the injector
+	Instantiated bool
+}
+
+// Dependencies returns the types the provider of the registered type depends on
+func (registration Registration) Dependencies() []reflect.Type {
+	deps := make([]reflect.Type, registration.Provider.NumIn())
+	for i := range deps {
+		deps[i] = registration.Provider.In(i)
+	}
+	return deps
+}
+
+// Has returns true in case a provider is registered for the given type reference, which follows
+// the same convention as the Provide* methods. Interface scanning is not taken into account.
+func (injector *Injector) Has(injectable interface{}) bool {
+	injector.mutex.Lock()
+	defer injector.mutex.Unlock()
+
+	_, registered := injector.injectables[keyOf(injectable)]
+	return registered
+}
+
+// Has returns true in case a provider is registered for T with the given injector:
+//
+//	if !katana.Has[*sql.DB](injector) {
+//		injector.ProvideSingleton(&sql.DB{}, OpenDB)
+//	}
+func Has[T any](injector *Injector) bool {
+	injector.mutex.Lock()
+	defer injector.mutex.Unlock()
+
+	_, registered := injector.injectables[reflect.TypeOf((*T)(nil)).Elem()]
+	return registered
+}
+
+// Registrations returns the types registered with the injector sorted by name. Like the other
+// introspection methods, it is safe to call while the injector is resolving singletons.
+func (injector *Injector) Registrations() []Registration {
+	injector.mutex.Lock()
+	defer injector.mutex.Unlock()
+
+	registrations := make([]Registration, 0, len(injector.injectables))
+	for typ, injectable := range injector.injectables {
+		// The *Lifecycle provided by every injector is not a registration of the user
+		if typ == reflect.TypeOf(injector.lifecycle) {
+			continue
+		}
+
+		_, instantiated := injector.instances[typ]
+		registrations = append(registrations, Registration{
+			Type:           typ,
+			InjectableType: injectable.Type,
+			Provider:       reflect.TypeOf(injectable.Provider),
+			Location:       injectable.location(),
+			Instantiated:   instantiated,
+		})
+	}
+
+	sort.Slice(registrations, func(i, j int) bool {
+		return registrations[i].Type.String() < registrations[j].Type.String()
+	})
+
+	return registrations
+}
+
+// Instantiated returns the types of the singletons instantiated and cached by the injector,
+// in instantiation order
+func (injector *Injector) Instantiated() []reflect.Type {
+	injector.mutex.Lock()
+	defer injector.mutex.Unlock()
+
+	return append([]reflect.Type(nil), injector.singletons...)
+}
+
+// callers returns the call stack of the current registration. Frames are resolved lazily
+// since registrations happen far more often than they are inspected. The stack is deep enough
+// to get past registrations made on behalf of the caller, e.g. by katana/config or katana/manifest.
+func callers() []uintptr {
+	pcs := make([]uintptr, 32)
+	return pcs[:runtime.Callers(3, pcs)]
+}
+
+// location returns the file and line of the first caller outside of katana's packages that
+// registered the injectable
+func (injectable *Injectable) location() string {
+	frames := runtime.CallersFrames(injectable.callers)
+	for {
+		frame, more := frames.Next()
+		if frame.Function == "" {
+			return ""
+		}
+		if !library(frame.Function) {
+			return fmt.Sprintf("%v:%v", frame.File, frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// library returns true in case the given function is declared by katana or any of its packages,
+// their tests excluded
+func library(function string) bool {
+	// Type arguments of generic functions may hold import paths of their own
+	if i := strings.IndexByte(function, '['); i >= 0 {
+		function = function[:i]
+	}
+
+	pkg := function
+	if slash := strings.LastIndexByte(pkg, '/'); slash >= 0 {
+		if dot := strings.IndexByte(pkg[slash:], '.'); dot >= 0 {
+			pkg = pkg[:slash+dot]
+		}
+	}
+
+	return (pkg == modulePath || strings.HasPrefix(pkg, modulePath+"/")) && !strings.HasSuffix(pkg, "_test")
+}
@@ -0,0 +1,99 @@
+package katana_test
+
+import (
+	"github.com/drborges/katana"
+	"github.com/drborges/katana/config"
+	"github.com/drborges/katana/katanatest"
+	"github.com/smartystreets/assertions/should"
+	. "github.com/smartystreets/goconvey/convey"
+	"reflect"
+	"testing"
+)
+
+func TestInjectorRegistrations(t *testing.T) {
+	Convey("Given I have an injector with a resolved singleton", t, func() {
+		injector := katana.New().
+			ProvideNew(&DependencyA{}, func(dep *Dependency) *DependencyA {
+				return &DependencyA{dep}
+			}).
+			ProvideSingleton(&Dependency{}, func() *Dependency {
+				return &Dependency{}
+			})
+
+		var dep *Dependency
+		injector.Resolve(&dep)
+
+		Convey("When I check whether types are registered", func() {
+			Convey("Then registered types are reported", func() {
+				So(injector.Has(&Dependency{}), should.BeTrue)
+				So(injector.Has(&DependencyB{}), should.BeFalse)
+				So(injector.Has((*InterfaceDependency)(nil)), should.BeFalse)
+				So(katana.Has[*DependencyA](injector), should.BeTrue)
+				So(katana.Has[*DependencyB](injector), should.BeFalse)
+			})
+		})
+
+		Convey("When I look up a registered type", func() {
+			injectable, found := injector.Lookup(&Dependency{})
+
+			Convey("Then its injectable is returned", func() {
+				So(found, should.BeTrue)
+				So(injectable.Type, should.Equal, katana.TypeSingleton)
+			})
+		})
+
+		Convey("When I look up a type that is not registered", func() {
+			_, found := injector.Lookup(&DependencyB{})
+
+			Convey("Then it is not found", func() {
+				So(found, should.BeFalse)
+			})
+		})
+
+		Convey("When I list its registrations", func() {
+			registrations := injector.Registrations()
+
+			Convey("Then the registered types are described sorted by name", func() {
+				So(len(registrations), should.Equal, 2)
+				So(registrations[0].Type, should.Equal, reflect.TypeOf(&Dependency{}))
+				So(registrations[0].InjectableType, should.Equal, katana.TypeSingleton)
+				So(registrations[0].Provider, should.Equal, reflect.TypeOf(func() *Dependency { return nil }))
+				So(registrations[0].Instantiated, should.BeTrue)
+				So(registrations[1].Type, should.Equal, reflect.TypeOf(&DependencyA{}))
+				So(registrations[1].InjectableType, should.Equal, katana.TypeNew)
+				So(registrations[1].Instantiated, should.BeFalse)
+				So(registrations[1].Dependencies(), should.Resemble, []reflect.Type{reflect.TypeOf(&Dependency{})})
+			})
+
+			Convey("Then registrations point to where they happened", func() {
+				So(registrations[0].Location, should.ContainSubstring, "registration_test.go:")
+				So(registrations[1].Location, should.ContainSubstring, "registration_test.go:")
+			})
+		})
+
+		Convey("When I list its instantiated singletons", func() {
+			Convey("Then the singletons are listed in instantiation order", func() {
+				So(injector.Instantiated(), should.Resemble, []reflect.Type{reflect.TypeOf(&Dependency{})})
+			})
+		})
+	})
+}
+
+func TestRegistrationLocation(t *testing.T) {
+	Convey("Given I register types through katana's packages", t, func() {
+		injector := katanatest.NewTestInjector(t, nil).
+			ProvideNew(&Dependency{}, func() *Dependency { return &Dependency{} })
+
+		config.Provide(injector.Injector, &struct{ Name string }{})
+
+		Convey("When I list its registrations", func() {
+			registrations := injector.Registrations()
+
+			Convey("Then registrations point to the caller rather than to katana's packages", func() {
+				So(len(registrations), should.Equal, 2)
+				So(registrations[0].Location, should.ContainSubstring, "registration_test.go:")
+				So(registrations[1].Location, should.ContainSubstring, "registration_test.go:")
+			})
+		})
+	})
+}
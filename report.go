@@ -0,0 +1,147 @@
+package katana
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Report describes the tree of dependencies visited by a single resolution, see
+// Injector#ResolveWithReport.
+type Report struct {
+	Roots    []*ReportNode `json:"roots"`
+	Duration time.Duration `json:"duration"`
+}
+
+// ReportNode is a type visited during a resolution, either served from the singleton cache
+// or provided by calling its provider.
+type ReportNode struct {
+	Type     string        `json:"type"`
+	Cached   bool          `json:"cached"`
+	Duration time.Duration `json:"duration,omitempty"`
+	// Location is the file and line where the type was registered
+	Location     string        `json:"location,omitempty"`
+	Err          string        `json:"error,omitempty"`
+	Dependencies []*ReportNode `json:"dependencies,omitempty"`
+}
+
+// ResolveWithReport works like Injector#Resolve, except the tree of types visited by the
+// resolution is reported along with the timing of the providers called and the location where
+// their types were registered.
+//
+// Rather than panicking, ResolveWithReport returns any resolution failure as an error, along
+// with the report of the resolution up to the failure. Reported resolutions never happen in
+// parallel, see Injector#Parallel.
+func (injector *Injector) ResolveWithReport(refs ...interface{}) (*Report, error) {
+	defer func(workers int) { injector.workers = workers }(injector.workers)
+	injector.workers = 0
+
+	reporter := &reporter{
+		injector: injector,
+		report:   &Report{},
+		depth:    len(injector.trace.Types),
+	}
+
+	observers := injector.observers
+	injector.observers = append(observers[:len(observers):len(observers)], reporter)
+	defer func() { injector.observers = observers }()
+
+	start := time.Now()
+	err := injector.tryResolve(refs...)
+	reporter.report.Duration = time.Since(start)
+
+	return reporter.report, err
+}
+
+// String renders the report as an indented text tree
+func (report *Report) String() string {
+	var b strings.Builder
+	for _, node := range report.Roots {
+		node.write(&b, 0)
+	}
+	return b.String()
+}
+
+// JSON renders the report as indented JSON, durations given in nanoseconds
+func (report *Report) JSON() ([]byte, error) {
+	return json.MarshalIndent(report, "", "  ")
+}
+
+func (node *ReportNode) write(b *strings.Builder, indent int) {
+	b.WriteString(strings.Repeat("  ", indent))
+	b.WriteString(node.Type)
+
+	switch {
+	case node.Cached:
+		b.WriteString(" (cached)")
+	case node.Location != "":
+		fmt.Fprintf(b, " (%v, %v)", node.Duration, node.Location)
+	default:
+		fmt.Fprintf(b, " (%v)", node.Duration)
+	}
+
+	if node.Err != "" {
+		fmt.Fprintf(b, " error: %v", node.Err)
+	}
+
+	b.WriteString("\n")
+	for _, dep := range node.Dependencies {
+		dep.write(b, indent+1)
+	}
+}
+
+// reporter is an Observer building a Report out of the notified resolution events
+type reporter struct {
+	injector *Injector
+	report   *Report
+	// depth is the size of the trace when the reported resolution started
+	depth int
+	// stack holds the nodes under resolution, from the outermost to the innermost
+	stack []*ReportNode
+}
+
+func (reporter *reporter) OnResolveStart(typ reflect.Type, path []string) {
+	node := &ReportNode{Type: typ.String()}
+
+	// Nodes left in the stack by failed resolutions are discarded
+	if depth := len(path) - reporter.depth; depth < len(reporter.stack) {
+		reporter.stack = reporter.stack[:depth]
+	}
+
+	if len(reporter.stack) == 0 {
+		reporter.report.Roots = append(reporter.report.Roots, node)
+	} else {
+		parent := reporter.stack[len(reporter.stack)-1]
+		parent.Dependencies = append(parent.Dependencies, node)
+	}
+
+	reporter.stack = append(reporter.stack, node)
+}
+
+func (reporter *reporter) OnProviderCalled(typ reflect.Type, duration time.Duration, err error) {
+	node := reporter.pop()
+	node.Duration = duration
+	if err != nil {
+		node.Err = err.Error()
+	}
+	if injectable, registered := reporter.injector.injectables[typ]; registered {
+		node.Location = injectable.location()
+	}
+}
+
+func (reporter *reporter) OnCacheHit(typ reflect.Type) {
+	reporter.pop().Cached = true
+}
+
+func (reporter *reporter) pop() *ReportNode {
+	if len(reporter.stack) == 0 {
+		return &ReportNode{}
+	}
+
+	last := len(reporter.stack) - 1
+	node := reporter.stack[last]
+	reporter.stack = reporter.stack[:last]
+	return node
+}
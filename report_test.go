@@ -0,0 +1,105 @@
+package katana_test
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/drborges/katana"
+	"github.com/smartystreets/assertions/should"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestInjectorResolveWithReport(t *testing.T) {
+	Convey("Given I have an injector with a provided instance", t, func() {
+		injector := katana.New().Provide(&Dependency{})
+
+		Convey("Then the report locates it where it was registered", func() {
+			var dep *Dependency
+			report, err := injector.ResolveWithReport(&dep)
+
+			So(err, should.BeNil)
+			So(report.Roots[0].Location, should.ContainSubstring, "report_test.go:")
+		})
+	})
+
+	Convey("Given I have an injector with a resolved singleton", t, func() {
+		injector := katana.New().
+			ProvideSingleton(&Dependency{}, func() *Dependency {
+				return &Dependency{}
+			}).
+			ProvideNew(&DependencyA{}, func(dep *Dependency) *DependencyA {
+				return &DependencyA{dep}
+			}).
+			ProvideNew(&DependencyB{}, func(depA *DependencyA) *DependencyB {
+				return &DependencyB{depA}
+			})
+
+		var dep *Dependency
+		injector.Resolve(&dep)
+
+		Convey("When I resolve dependencies with a report", func() {
+			var depB *DependencyB
+			var depA *DependencyA
+			report, err := injector.ResolveWithReport(&depB, &depA)
+
+			Convey("Then the dependencies are resolved", func() {
+				So(err, should.BeNil)
+				So(depB.Dep.Dep, should.Equal, dep)
+				So(depA.Dep, should.Equal, dep)
+			})
+
+			Convey("Then the report holds the tree of visited types", func() {
+				So(len(report.Roots), should.Equal, 2)
+
+				root := report.Roots[0]
+				So(root.Type, should.Equal, "*katana_test.DependencyB")
+				So(root.Cached, should.BeFalse)
+				So(root.Location, should.ContainSubstring, "report_test.go:")
+				So(len(root.Dependencies), should.Equal, 1)
+				So(root.Dependencies[0].Type, should.Equal, "*katana_test.DependencyA")
+				So(root.Dependencies[0].Dependencies[0].Type, should.Equal, "*katana_test.Dependency")
+				So(root.Dependencies[0].Dependencies[0].Cached, should.BeTrue)
+
+				So(report.Roots[1].Type, should.Equal, "*katana_test.DependencyA")
+				So(report.Roots[1].Dependencies[0].Cached, should.BeTrue)
+			})
+
+			Convey("Then the report renders as an indented text tree", func() {
+				text := report.String()
+
+				So(text, should.StartWith, "*katana_test.DependencyB (")
+				So(text, should.ContainSubstring, "\n  *katana_test.DependencyA (")
+				So(text, should.ContainSubstring, "\n    *katana_test.Dependency (cached)\n")
+			})
+
+			Convey("Then the report renders as JSON", func() {
+				data, err := report.JSON()
+				So(err, should.BeNil)
+
+				var decoded katana.Report
+				So(json.Unmarshal(data, &decoded), should.BeNil)
+				So(decoded.Roots[0].Type, should.Equal, "*katana_test.DependencyB")
+				So(decoded.Roots[0].Dependencies[0].Dependencies[0].Cached, should.BeTrue)
+			})
+
+			Convey("Then the report observer is no longer notified", func() {
+				injector.Resolve(&depB)
+				So(len(report.Roots), should.Equal, 2)
+			})
+		})
+
+		Convey("When the resolution fails", func() {
+			injector.Override(&DependencyA{}, katana.TypeNew, func(dep *Dependency) *DependencyA {
+				panic(errors.New("boom"))
+			})
+
+			var depB *DependencyB
+			report, err := injector.ResolveWithReport(&depB)
+
+			Convey("Then the error is returned along with the report up to the failure", func() {
+				So(err, should.Resemble, errors.New("boom"))
+				So(report.Roots[0].Dependencies[0].Err, should.Equal, "boom")
+			})
+		})
+	})
+}
@@ -0,0 +1,113 @@
+package katana
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// Start instantiates all eager singletons, registered via Injector#ProvideEagerSingleton, in
+// registration order. Dependencies of each eager singleton are resolved first, so the instances
+// are created in dependency order.
+//
+// Pending conditional bindings are evaluated before any eager singleton is instantiated, see
+// Injector#When.
+//
+// Eager singleton providers taking a context.Context argument receive ctx. With parallel
+// resolution enabled, independent eager singletons are instantiated concurrently.
+//
+// Start fails on the first provider error, cyclic dependency or missing provider, as well as when
+// the given context is done. In that case every singleton instantiated during the call is evicted
+// from the injector and, if it implements io.Closer, closed in reverse instantiation order.
+func (injector *Injector) Start(ctx context.Context) (err error) {
+	defer injector.bind(ctx)()
+	created := len(injector.singletons)
+
+	defer func() {
+		if err != nil {
+			injector.evict(injector.singletons[created:])
+		}
+	}()
+
+	if err := injector.evaluateConditions(); err != nil {
+		return err
+	}
+
+	refs := make([]interface{}, len(injector.eager))
+	for i, typ := range injector.eager {
+		refs[i] = reflect.New(typ).Interface()
+	}
+
+	if injector.workers > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return injector.tryResolve(refs...)
+	}
+
+	for _, ref := range refs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := injector.tryResolve(ref); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// tryResolve works like Injector#Resolve, but returns the resolution failure as an error
+// rather than panicking.
+func (injector *Injector) tryResolve(refs ...interface{}) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = asError(r)
+			injector.resetTrace()
+		}
+	}()
+
+	injector.Resolve(refs...)
+	return nil
+}
+
+// Close evicts the singletons instantiated by the injector, closing in reverse instantiation
+// order the ones implementing io.Closer. Singletons inherited from the injector it was cloned
+// from are left untouched. Returns the errors returned by the closed instances, if any.
+func (injector *Injector) Close() error {
+	return injector.evict(injector.singletons[injector.inherited:])
+}
+
+// evict removes the given trailing singleton types from the instance cache, closing in reverse
+// order the instances implementing io.Closer.
+func (injector *Injector) evict(types []reflect.Type) error {
+	var errs []error
+	for i := len(types) - 1; i >= 0; i-- {
+		typ := types[i]
+		if closer, ok := injector.instances[typ].(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	injector.mutex.Lock()
+	for _, typ := range types {
+		delete(injector.instances, typ)
+	}
+	injector.singletons = injector.singletons[:len(injector.singletons)-len(types)]
+	injector.mutex.Unlock()
+
+	return errors.Join(errs...)
+}
+
+// asError converts a recovered panic value into an error
+func asError(r interface{}) error {
+	if err, ok := r.(error); ok {
+		return err
+	}
+	return fmt.Errorf("%v", r)
+}
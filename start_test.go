@@ -0,0 +1,140 @@
+package katana_test
+
+import (
+	"context"
+	"errors"
+	"github.com/drborges/katana"
+	"github.com/smartystreets/assertions/should"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+type Connection struct {
+	closed bool
+}
+
+func (conn *Connection) Close() error {
+	conn.closed = true
+	return nil
+}
+
+type Repository struct {
+	Conn *Connection
+}
+
+func TestInjectorStart(t *testing.T) {
+	Convey("Given I have eager singleton providers depending on each other", t, func() {
+		var calls []string
+
+		injector := katana.New().
+			ProvideEagerSingleton(&Repository{}, func(conn *Connection) *Repository {
+				calls = append(calls, "repository")
+				return &Repository{conn}
+			}).
+			ProvideEagerSingleton(&Connection{}, func() *Connection {
+				calls = append(calls, "connection")
+				return &Connection{}
+			})
+
+		Convey("When I start the injector", func() {
+			err := injector.Start(context.Background())
+
+			Convey("Then the eager singletons are instantiated in dependency order", func() {
+				So(err, should.BeNil)
+				So(calls, should.Resemble, []string{"connection", "repository"})
+			})
+
+			Convey("And resolving them yields the instances created upfront", func() {
+				var repo *Repository
+				injector.Resolve(&repo)
+
+				So(repo.Conn, should.NotBeNil)
+				So(calls, should.HaveLength, 2)
+			})
+		})
+
+		Convey("When I start the injector with a cancelled context", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			err := injector.Start(ctx)
+
+			Convey("Then it fails with the context error", func() {
+				So(err, should.Equal, context.Canceled)
+				So(calls, should.BeEmpty)
+			})
+		})
+	})
+
+	Convey("Given I have an eager singleton whose provider fails after its dependencies are created", t, func() {
+		var conn *Connection
+		failure := errors.New("cannot connect")
+
+		injector := katana.New().
+			ProvideEagerSingleton(&Connection{}, func() *Connection {
+				conn = &Connection{}
+				return conn
+			}).
+			ProvideEagerSingleton(&Repository{}, func(conn *Connection) *Repository {
+				panic(failure)
+			})
+
+		Convey("When I start the injector", func() {
+			err := injector.Start(context.Background())
+
+			Convey("Then it fails with the provider error", func() {
+				So(err, should.Equal, failure)
+			})
+
+			Convey("And the singletons created so far are closed and evicted", func() {
+				closed := conn
+				So(closed.closed, should.BeTrue)
+
+				var other *Connection
+				injector.Resolve(&other)
+				So(other, should.NotPointTo, closed)
+			})
+		})
+	})
+
+	Convey("Given I have an eager singleton with a missing dependency", t, func() {
+		injector := katana.New().ProvideEagerSingleton(&Repository{}, func(conn *Connection) *Repository {
+			return &Repository{conn}
+		})
+
+		Convey("Then starting the injector returns a no such provider error", func() {
+			So(injector.Start(context.Background()), should.HaveSameTypeAs, katana.ErrNoSuchProvider{})
+		})
+	})
+}
+
+func TestInjectorClose(t *testing.T) {
+	Convey("Given I have a cloned injector with inherited and own singletons", t, func() {
+		injector := katana.New().ProvideSingleton(&Connection{}, func() *Connection {
+			return &Connection{}
+		})
+
+		var inherited *Connection
+		injector.Resolve(&inherited)
+
+		clone := injector.Clone().ProvideSingleton(&Repository{}, func() *Repository {
+			return &Repository{&Connection{}}
+		})
+
+		var repo *Repository
+		clone.Resolve(&repo)
+
+		Convey("When I close the cloned injector", func() {
+			err := clone.Close()
+
+			Convey("Then only the singletons it instantiated are evicted", func() {
+				So(err, should.BeNil)
+				So(inherited.closed, should.BeFalse)
+
+				var other *Repository
+				clone.Resolve(&other)
+				So(other, should.NotPointTo, repo)
+			})
+		})
+	})
+}